@@ -2,4 +2,7 @@ module shuttletracker
 
 go 1.25.4
 
-require golang.org/x/crypto v0.44.0 // indirect
+require (
+	golang.org/x/crypto v0.44.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,226 @@
+// Package auth handles the built-in accounts, login sessions and calendar
+// feed tokens.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
+)
+
+// SessionCookie is the name of the cookie holding the session ID
+const SessionCookie = "session_id"
+
+// SessionTTL is how long a login lasts, matching the session cookie's lifetime
+const SessionTTL = 7 * 24 * time.Hour
+
+// User represents an authenticated account with role-based access
+type User struct {
+	Username     string
+	PasswordHash string
+	Role         string // "valet", "desk", or "demo"
+}
+
+// session is a logged-in user and when their login runs out
+type session struct {
+	username string
+	expires  time.Time
+}
+
+// Store holds the accounts and their logged-in sessions
+type Store struct {
+	users map[string]User // Hardcoded users - in production these would be in a database
+	clock clock.Clock
+
+	mu       sync.RWMutex
+	sessions map[string]session // sessionID -> session
+}
+
+// NewStore creates the built-in accounts with passwords from the config.
+// Sessions expire SessionTTL after login by clk.
+func NewStore(cfg config.AuthConfig, clk clock.Clock) *Store {
+	return &Store{
+		clock: clk,
+		users: map[string]User{
+			"valet": {
+				Username:     "valet",
+				PasswordHash: HashPassword(cfg.ValetPassword, cfg.BcryptCost),
+				Role:         "valet",
+			},
+			"desk": {
+				Username:     "desk",
+				PasswordHash: HashPassword(cfg.DeskPassword, cfg.BcryptCost),
+				Role:         "desk",
+			},
+			"demo": {
+				Username:     "demo",
+				PasswordHash: HashPassword(cfg.DemoPassword, cfg.BcryptCost),
+				Role:         "demo",
+			},
+		},
+		sessions: map[string]session{},
+	}
+}
+
+// HashPassword creates a bcrypt hash from plain text password
+func HashPassword(password string, cost int) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		panic(err)
+	}
+	return string(hash)
+}
+
+// CheckPassword verifies a password against a bcrypt hash
+func CheckPassword(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+// NewToken creates a cryptographically secure random ID for sessions and feed tokens
+func NewToken() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// User returns the account with the given username
+func (s *Store) User(username string) (User, bool) {
+	user, exists := s.users[username]
+	return user, exists
+}
+
+// Authenticate returns the account if the username and password match
+func (s *Store) Authenticate(username, password string) (User, bool) {
+	user, exists := s.users[username]
+	if !exists || !CheckPassword(password, user.PasswordHash) {
+		return User{}, false
+	}
+	return user, true
+}
+
+// CreateSession generates and stores a new session for a user
+func (s *Store) CreateSession(username string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	// Drop expired sessions so the map doesn't grow with every login
+	for id, existing := range s.sessions {
+		if !now.Before(existing.expires) {
+			delete(s.sessions, id)
+		}
+	}
+
+	sessionID := NewToken()
+	s.sessions[sessionID] = session{username: username, expires: now.Add(SessionTTL)}
+	return sessionID
+}
+
+// Session retrieves username from session ID, or "" if it is unknown or expired
+func (s *Store) Session(sessionID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, exists := s.sessions[sessionID]
+	if !exists || !s.clock.Now().Before(found.expires) {
+		return ""
+	}
+	return found.username
+}
+
+// ActiveSessions returns the number of logged-in sessions that haven't expired
+func (s *Store) ActiveSessions() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.clock.Now()
+	active := 0
+	for _, found := range s.sessions {
+		if now.Before(found.expires) {
+			active++
+		}
+	}
+	return active
+}
+
+// DeleteSession removes a session (logout)
+func (s *Store) DeleteSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+}
+
+// CurrentUser extracts User from request session cookie
+func (s *Store) CurrentUser(r *http.Request) *User {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return nil
+	}
+
+	username := s.Session(cookie.Value)
+	if username == "" {
+		return nil
+	}
+
+	user, exists := s.users[username]
+	if !exists {
+		return nil
+	}
+
+	return &user
+}
+
+// CurrentUsername returns the logged-in username, or "" if there is none
+func (s *Store) CurrentUsername(r *http.Request) string {
+	user := s.CurrentUser(r)
+	if user == nil {
+		return ""
+	}
+	return user.Username
+}
+
+// RequireRole is middleware that only lets users with one of the given roles through.
+// It expects to run inside RequireAuth.
+func (s *Store) RequireRole(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := s.CurrentUser(r)
+		if user != nil {
+			for _, role := range roles {
+				if user.Role == role {
+					next(w, r)
+					return
+				}
+			}
+		}
+
+		http.Error(w, "You don't have access to this page", http.StatusForbidden)
+	}
+}
+
+// RequireAuth is middleware that protects routes requiring authentication
+func (s *Store) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(SessionCookie)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		username := s.Session(cookie.Value)
+		if username == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		next(w, r)
+	}
+}
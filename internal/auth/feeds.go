@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"shuttletracker/internal/storage"
+)
+
+// FeedTokens maps calendar feed tokens to usernames, persisted so
+// subscriptions survive restarts
+type FeedTokens struct {
+	path string
+
+	mu     sync.Mutex
+	tokens map[string]string // token -> username, loaded on first use
+}
+
+// NewFeedTokens returns the feed tokens saved at path
+func NewFeedTokens(path string) *FeedTokens {
+	return &FeedTokens{path: path}
+}
+
+// loadLocked reads the tokens on first use. The caller must hold f.mu.
+func (f *FeedTokens) loadLocked() error {
+	if f.tokens != nil {
+		return nil
+	}
+	tokens := map[string]string{}
+	if err := storage.ReadJSON(f.path, &tokens); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to read calendar tokens: %s", err.Error())
+	}
+	f.tokens = tokens
+	return nil
+}
+
+// Token returns the user's feed token, creating one if they don't have one.
+// With regenerate set, any existing token is revoked and replaced.
+func (f *FeedTokens) Token(username string, regenerate bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.loadLocked(); err != nil {
+		return "", err
+	}
+
+	for token, owner := range f.tokens {
+		if owner == username {
+			if !regenerate {
+				return token, nil
+			}
+			delete(f.tokens, token)
+		}
+	}
+
+	token := NewToken()
+	f.tokens[token] = username
+	if err := storage.WriteJSON(f.path, f.tokens); err != nil {
+		delete(f.tokens, token)
+		return "", err
+	}
+	return token, nil
+}
+
+// Owner returns the username a feed token belongs to, or "" if it isn't valid
+func (f *FeedTokens) Owner(token string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if token == "" || f.loadLocked() != nil {
+		return ""
+	}
+	return f.tokens[token]
+}
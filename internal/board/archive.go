@@ -0,0 +1,143 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/storage"
+)
+
+// ArchivedDay is the stored board for a past service day
+type ArchivedDay struct {
+	Date       string    // Service date (YYYY-MM-DD)
+	ArchivedAt time.Time // When the board was archived
+	Flights    []Flight
+	Audit      []AuditEntry // Changes made to the day's flights
+}
+
+// Archive stores one JSON file per past service day
+type Archive struct {
+	dir string
+}
+
+// NewArchive returns an archive kept in dir
+func NewArchive(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// path returns the file for a service date, rejecting anything that isn't a date
+func (a *Archive) path(date string) (string, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", fmt.Errorf("Invalid date: %s", date)
+	}
+	return filepath.Join(a.dir, date+".json"), nil
+}
+
+// Save stores the flights for a service day. Flights archived earlier for the
+// same day (e.g. before a restart) are kept, and demo flights are left out
+// since they never happened.
+func (a *Archive) Save(date string, dayFlights []Flight, at time.Time) error {
+	path, err := a.path(date)
+	if err != nil {
+		return err
+	}
+
+	day, err := a.Load(date)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	day.Date = date
+	day.ArchivedAt = at
+	for _, flight := range dayFlights {
+		if !flight.IsDemo {
+			day.Flights = append(day.Flights, flight)
+		}
+	}
+
+	return storage.WriteJSON(path, day)
+}
+
+// SaveAudit adds the changes made to a service day's flights to its
+// archived board
+func (a *Archive) SaveAudit(date string, entries []AuditEntry) error {
+	path, err := a.path(date)
+	if err != nil {
+		return err
+	}
+
+	day, err := a.Load(date)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	day.Date = date
+	day.Audit = append(day.Audit, entries...)
+
+	return storage.WriteJSON(path, day)
+}
+
+// Load reads the stored board for a service day
+func (a *Archive) Load(date string) (ArchivedDay, error) {
+	path, err := a.path(date)
+	if err != nil {
+		return ArchivedDay{}, err
+	}
+
+	var day ArchivedDay
+	if err := storage.ReadJSON(path, &day); err != nil {
+		if os.IsNotExist(err) {
+			return ArchivedDay{}, err
+		}
+		return ArchivedDay{}, fmt.Errorf("Failed to read archive for %s: %s", date, err.Error())
+	}
+	return day, nil
+}
+
+// Days returns every archived service date, newest first
+func (a *Archive) Days() ([]string, error) {
+	entries, err := os.ReadDir(a.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var days []string
+	for _, entry := range entries {
+		date, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err == nil {
+			days = append(days, date)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(days)))
+	return days, nil
+}
+
+// Range reads every archived day between from and to inclusive, oldest first
+func (a *Archive) Range(from, to string) ([]ArchivedDay, error) {
+	dates, err := a.Days()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dates)
+
+	var days []ArchivedDay
+	for _, date := range dates {
+		if date < from || date > to {
+			continue
+		}
+		day, err := a.Load(date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
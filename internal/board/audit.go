@@ -0,0 +1,111 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"shuttletracker/internal/storage"
+)
+
+// AuditEntry records a single change made to a tracked flight
+type AuditEntry struct {
+	Time     time.Time // When the change was made
+	Username string    // Who made the change
+	FlightID int       // Which flight was changed
+	Action   string    // What kind of change ("edit", etc.)
+	Changes  []string  // Human-readable description of each changed field
+}
+
+// AuditLog is the log of flight changes since the last rollover, persisted
+// to a JSON file so it survives a restart
+type AuditLog struct {
+	path string
+
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewAuditLog returns an empty audit log saved to path
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path, entries: []AuditEntry{}}
+}
+
+// Load reads the audit log from disk
+func (l *AuditLog) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var stored []AuditEntry
+	if err := storage.ReadJSON(l.path, &stored); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Failed to read audit log: %s", err.Error())
+	}
+	l.entries = stored
+	return nil
+}
+
+// Record appends an entry made at the given time to the audit log
+func (l *AuditLog) Record(username string, flightID int, action string, changes []string, at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, AuditEntry{
+		Time:     at,
+		Username: username,
+		FlightID: flightID,
+		Action:   action,
+		Changes:  changes,
+	})
+	return storage.WriteJSON(l.path, l.entries)
+}
+
+// Entries returns a copy of the audit log, oldest first
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]AuditEntry(nil), l.entries...)
+}
+
+// ArchiveBefore hands the entries made before cutoff to save and drops them
+// from the log once save succeeds
+func (l *AuditLog) ArchiveBefore(cutoff time.Time, save func([]AuditEntry) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var archived, kept []AuditEntry
+	for _, entry := range l.entries {
+		if entry.Time.Before(cutoff) {
+			archived = append(archived, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+	if err := save(archived); err != nil {
+		return err
+	}
+	l.entries = append([]AuditEntry{}, kept...)
+	return storage.WriteJSON(l.path, l.entries)
+}
+
+// DiffFlights describes the user-editable fields that differ between two versions of a flight
+func DiffFlights(old, updated Flight) []string {
+	var changes []string
+	if old.FlightNumber != updated.FlightNumber {
+		changes = append(changes, fmt.Sprintf("flight number %s -> %s", old.FlightNumber, updated.FlightNumber))
+	}
+	if old.Type != updated.Type {
+		changes = append(changes, fmt.Sprintf("type %s -> %s", old.Type, updated.Type))
+	}
+	if old.CrewCount != updated.CrewCount {
+		changes = append(changes, fmt.Sprintf("crew count %d -> %d", old.CrewCount, updated.CrewCount))
+	}
+	return changes
+}
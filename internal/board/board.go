@@ -0,0 +1,169 @@
+package board
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"shuttletracker/internal/storage"
+)
+
+// ErrFlightNotFound is returned when a flight ID isn't on the board
+var ErrFlightNotFound = errors.New("Flight not found on the board")
+
+// Board holds the flights for the current service day
+type Board struct {
+	mu      sync.Mutex
+	flights []Flight
+	nextID  int
+}
+
+// Snapshot is the live board saved at shutdown so a restart picks up where it left off
+type Snapshot struct {
+	ServiceDate string // Service date the board belongs to (YYYY-MM-DD)
+	SavedAt     time.Time
+	NextID      int
+	Flights     []Flight
+}
+
+// New returns an empty board
+func New() *Board {
+	return &Board{flights: []Flight{}, nextID: 1}
+}
+
+// clone copies a flight along with its roster and stage history, so copies
+// handed out or taken in by the board don't share them with the board
+func (f Flight) clone() Flight {
+	f.Crew = slices.Clone(f.Crew)
+	f.StageHistory = slices.Clone(f.StageHistory)
+	return f
+}
+
+// Add assigns the next ID to a flight and puts it on the board
+func (b *Board) Add(flight Flight) Flight {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	flight.ID = b.nextID
+	b.nextID++
+	b.flights = append(b.flights, flight.clone())
+	return flight
+}
+
+// Get returns a copy of the flight with the given ID
+func (b *Board) Get(id int) (Flight, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, flight := range b.flights {
+		if flight.ID == id {
+			return flight.clone(), true
+		}
+	}
+	return Flight{}, false
+}
+
+// Update applies fn to the flight with the given ID while holding the board lock.
+// If fn returns an error the flight is left unchanged.
+func (b *Board) Update(id int, fn func(*Flight) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := range b.flights {
+		if b.flights[i].ID == id {
+			updated := b.flights[i].clone()
+			if err := fn(&updated); err != nil {
+				return err
+			}
+			b.flights[i] = updated
+			return nil
+		}
+	}
+	return ErrFlightNotFound
+}
+
+// Remove deletes a flight from the board
+func (b *Board) Remove(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, flight := range b.flights {
+		if flight.ID == id {
+			b.flights = append(b.flights[:i], b.flights[i+1:]...)
+			return
+		}
+	}
+}
+
+// Sorted returns a copy of the board sorted chronologically by expected arrival
+func (b *Board) Sorted() []Flight {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sorted := make([]Flight, len(b.flights))
+	for i, flight := range b.flights {
+		sorted[i] = flight.clone()
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SortTime.Before(sorted[j].SortTime)
+	})
+	return sorted
+}
+
+// ArchiveAndClear passes the board to save and empties it once save succeeds
+func (b *Board) ArchiveAndClear(save func([]Flight) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := save(b.flights); err != nil {
+		return err
+	}
+	b.flights = []Flight{}
+	return nil
+}
+
+// Save writes the board to path so it survives a restart
+func (b *Board) Save(path, date string, at time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return storage.WriteJSON(path, Snapshot{
+		ServiceDate: date,
+		SavedAt:     at,
+		NextID:      b.nextID,
+		Flights:     b.flights,
+	})
+}
+
+// Restore loads the board saved at the last shutdown. A board from an earlier
+// service day missed its rollover while we were down, so it goes to the
+// archive instead. The saved file is removed once used so it is never
+// restored twice.
+func (b *Board) Restore(path, today string, archive *Archive, now time.Time) (int, error) {
+	var snapshot Snapshot
+	if err := storage.ReadJSON(path, &snapshot); err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if snapshot.ServiceDate != today {
+		if err := archive.Save(snapshot.ServiceDate, snapshot.Flights, now); err != nil {
+			return 0, err
+		}
+		snapshot.Flights = nil
+	}
+
+	b.mu.Lock()
+	b.flights = append(b.flights, snapshot.Flights...)
+	if snapshot.NextID > b.nextID {
+		b.nextID = snapshot.NextID
+	}
+	b.mu.Unlock()
+
+	return len(snapshot.Flights), os.Remove(path)
+}
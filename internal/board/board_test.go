@@ -0,0 +1,143 @@
+package board
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveAndClear(t *testing.T) {
+	archive := NewArchive(t.TempDir())
+	board := New()
+	board.Add(Flight{FlightNumber: "AA100", CrewCount: 3})
+	board.Add(Flight{FlightNumber: "DL200", CrewCount: 2})
+
+	save := func(dayFlights []Flight) error {
+		return archive.Save("2025-03-01", dayFlights, time.Now())
+	}
+	if err := board.ArchiveAndClear(save); err != nil {
+		t.Fatalf("ArchiveAndClear failed: %v", err)
+	}
+	if flights := board.Sorted(); len(flights) != 0 {
+		t.Errorf("Expected an empty board after archiving, got %d flights", len(flights))
+	}
+
+	day, err := archive.Load("2025-03-01")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(day.Flights) != 2 {
+		t.Errorf("Expected 2 archived flights, got %d", len(day.Flights))
+	}
+
+	// Archiving the same day again keeps what was archived before, but not
+	// demo flights
+	board.Add(Flight{FlightNumber: "UA300", CrewCount: 1})
+	board.Add(Flight{FlightNumber: "WN400", CrewCount: 2, IsDemo: true})
+	if err := board.ArchiveAndClear(save); err != nil {
+		t.Fatalf("Second ArchiveAndClear failed: %v", err)
+	}
+	day, _ = archive.Load("2025-03-01")
+	if len(day.Flights) != 3 {
+		t.Errorf("Expected 3 archived flights after archiving again, got %d", len(day.Flights))
+	}
+
+	days, _ := archive.Days()
+	if len(days) != 1 || days[0] != "2025-03-01" {
+		t.Errorf("Expected archive to list 2025-03-01, got %v", days)
+	}
+}
+
+func TestArchivePathRejectsNonDates(t *testing.T) {
+	if _, err := NewArchive(t.TempDir()).Load("../../etc/passwd"); err == nil {
+		t.Error("Expected a non-date archive name to be rejected")
+	}
+}
+
+func TestSaveAndRestoreBoard(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "board.json")
+	archive := NewArchive(filepath.Join(dir, "archive"))
+
+	saved := New()
+	saved.Add(Flight{FlightNumber: "AA100", CrewCount: 3})
+	if err := saved.Save(path, "2025-03-01", time.Now()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	board := New()
+	restored, err := board.Restore(path, "2025-03-01", archive, time.Now())
+	if err != nil || restored != 1 {
+		t.Fatalf("Expected 1 restored flight, got %d (%v)", restored, err)
+	}
+	flights := board.Sorted()
+	if len(flights) != 1 || flights[0].FlightNumber != "AA100" {
+		t.Errorf("Board not restored: %+v", flights)
+	}
+	if added := board.Add(Flight{FlightNumber: "DL200"}); added.ID == flights[0].ID {
+		t.Errorf("Expected new flights to keep getting fresh IDs, got %d twice", added.ID)
+	}
+
+	// The saved board is used only once
+	if restored, _ := board.Restore(path, "2025-03-01", archive, time.Now()); restored != 0 {
+		t.Errorf("Expected the saved board to be consumed, restored %d again", restored)
+	}
+}
+
+func TestRestoreBoardArchivesPastDay(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "board.json")
+	archive := NewArchive(filepath.Join(dir, "archive"))
+
+	saved := New()
+	saved.Add(Flight{FlightNumber: "DL200", CrewCount: 2})
+	if err := saved.Save(path, "2025-03-01", time.Now()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	board := New()
+	restored, err := board.Restore(path, "2025-03-02", archive, time.Now())
+	if err != nil || restored != 0 {
+		t.Fatalf("Expected nothing restored onto a new day, got %d (%v)", restored, err)
+	}
+	if flights := board.Sorted(); len(flights) != 0 {
+		t.Errorf("Expected an empty board, got %+v", flights)
+	}
+
+	day, err := archive.Load("2025-03-01")
+	if err != nil || len(day.Flights) != 1 {
+		t.Errorf("Expected the missed day to be archived, got %+v (%v)", day, err)
+	}
+}
+
+func TestBoardCopiesDontShareTheRoster(t *testing.T) {
+	board := New()
+	flight := board.Add(Flight{FlightNumber: "AA100", Crew: []CrewMember{{ID: 1, Name: "Ana Ruiz"}, {ID: 2, Name: "Ben Cole"}}})
+
+	// Check-ins run alongside page renders reading the board
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 200 {
+			board.Update(flight.ID, func(current *Flight) error {
+				current.Crew[0].CheckedIn = !current.Crew[0].CheckedIn
+				return nil
+			})
+		}
+	}()
+	for range 200 {
+		for _, sorted := range board.Sorted() {
+			sorted.CheckedInCount()
+		}
+	}
+	<-done
+
+	// A failed update leaves the roster as it was
+	board.Update(flight.ID, func(current *Flight) error {
+		current.Crew = append(current.Crew[:0], current.Crew[1:]...)
+		return ErrFlightNotFound
+	})
+	if got, _ := board.Get(flight.ID); len(got.Crew) != 2 || got.Crew[0].Name != "Ana Ruiz" {
+		t.Errorf("Expected a failed update to leave the roster alone, got %+v", got.Crew)
+	}
+}
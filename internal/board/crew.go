@@ -0,0 +1,45 @@
+package board
+
+// CrewPositions are the positions offered when adding a crew member
+var CrewPositions = []string{"Captain", "First Officer", "Flight Attendant", "Deadhead", "Other"}
+
+// CheckedInCount returns how many rostered crew members have been checked in
+func (f Flight) CheckedInCount() int {
+	count := 0
+	for _, member := range f.Crew {
+		if member.CheckedIn {
+			count++
+		}
+	}
+	return count
+}
+
+// CrewPositions returns the positions for the roster form
+func (f Flight) CrewPositions() []string {
+	return CrewPositions
+}
+
+// SyncCrewCount derives the crew count from the roster when one exists. The
+// count entered by hand is kept aside and comes back if the roster is emptied.
+func SyncCrewCount(flight *Flight) {
+	switch {
+	case len(flight.Crew) > 0:
+		if flight.ManualCrewCount == 0 {
+			flight.ManualCrewCount = flight.CrewCount
+		}
+		flight.CrewCount = len(flight.Crew)
+	case flight.ManualCrewCount > 0:
+		flight.CrewCount = flight.ManualCrewCount
+		flight.ManualCrewCount = 0
+	}
+}
+
+// CrewMemberIndex finds a crew member by ID, returning -1 if they aren't on the roster
+func CrewMemberIndex(flight *Flight, memberID int) int {
+	for i, member := range flight.Crew {
+		if member.ID == memberID {
+			return i
+		}
+	}
+	return -1
+}
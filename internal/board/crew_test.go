@@ -0,0 +1,32 @@
+package board
+
+import "testing"
+
+func TestSyncCrewCountRestoresCountWhenRosterEmptied(t *testing.T) {
+	flight := Flight{FlightNumber: "AA100", CrewCount: 5}
+
+	flight.Crew = []CrewMember{{ID: 1, Name: "Ana Ruiz"}, {ID: 2, Name: "Ben Cole"}}
+	SyncCrewCount(&flight)
+	if flight.CrewCount != 2 {
+		t.Errorf("Expected the roster to set the crew count to 2, got %d", flight.CrewCount)
+	}
+
+	flight.Crew = flight.Crew[:1]
+	SyncCrewCount(&flight)
+	if flight.CrewCount != 1 {
+		t.Errorf("Expected the crew count to follow the roster down to 1, got %d", flight.CrewCount)
+	}
+
+	flight.Crew = nil
+	SyncCrewCount(&flight)
+	if flight.CrewCount != 5 {
+		t.Errorf("Expected the count entered by hand back once the roster is empty, got %d", flight.CrewCount)
+	}
+
+	// A count edited afterwards sticks
+	flight.CrewCount = 3
+	SyncCrewCount(&flight)
+	if flight.CrewCount != 3 {
+		t.Errorf("Expected an edited count to be kept without a roster, got %d", flight.CrewCount)
+	}
+}
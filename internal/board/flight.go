@@ -0,0 +1,130 @@
+// Package board holds the day's flights and everything stored about them:
+// pickup stages, crew rosters, the audit log, archived days and recurring
+// schedules.
+package board
+
+import "time"
+
+// Flight represents a single flight with shuttle coordination details
+type Flight struct {
+	ID               int       // Unique identifier for this flight
+	FlightNumber     string    // Flight number (e.g., "AA100")
+	Airline          string    // Airline name
+	Status           string    // Flight status (scheduled, active, landed, etc.)
+	ScheduledArrival string    // Original scheduled arrival time (formatted)
+	ExpectedArrival  string    // Expected arrival time accounting for delays (formatted)
+	Delay            int       // Delay in minutes
+	IsDelayed        bool      // Whether the flight is delayed
+	Type             string    // "pickup", "dropoff", or "both"
+	CrewCount        int       // Number of crew members to transport
+	Note             string    // Optional note for this flight
+	SortTime         time.Time // Used for sorting flights chronologically
+	LastEditedBy     string    // Username of the last person to edit this flight
+	LastEditedAt     time.Time // When the flight was last edited
+	Stage            string    // Operational stage (pending, dispatched, waiting, picked_up, completed, no_show)
+	StageHistory     []StageChange
+	ScheduleID       int          // Recurring schedule that created this flight (0 if added by hand)
+	Crew             []CrewMember // Optional roster; when present CrewCount is derived from it
+	ManualCrewCount  int          // Crew count entered before the roster replaced it, restored if the roster is emptied
+	IsDemo           bool         // Whether this is simulated data added by a demo account
+	Departing        bool         // Tracked from the home airport's departures, so the times are when it leaves
+
+	// Where to meet the flight at the home airport: the arrival terminal and
+	// gate, or the departure ones for departing flights
+	Terminal     string
+	Gate         string
+	BaggageClaim string
+
+	Origin          string // Airport codes, e.g. "DFW"
+	OriginCity      string
+	Destination     string
+	DestinationCity string
+	AircraftType    string // e.g. "B738"
+	Registration    string // Tail number, e.g. "N123AA"
+	Source          string // Provider the flight data last came from, e.g. "flightaware"
+
+	PreviousGate  string    // Terminal and gate before the last change, e.g. "Terminal B, Gate B12"
+	GateChangedAt time.Time // When a refresh last moved the flight to a different terminal or gate
+
+	AlertID     string    // Push alert registered with the provider for this flight, if any
+	RefreshedAt time.Time // When provider data was last applied, by the poller or a pushed alert
+}
+
+// CrewMember is one person on a flight's roster
+type CrewMember struct {
+	ID          int
+	Name        string
+	Position    string // Captain, First Officer, Flight Attendant, etc.
+	Room        string // Hotel room number
+	Phone       string
+	CheckedIn   bool      // Whether the valet has collected this person
+	CheckedInAt time.Time // When they were checked in
+}
+
+// StageChange records a single timestamped stage transition
+type StageChange struct {
+	From     string
+	To       string
+	Time     time.Time
+	Username string
+}
+
+// ApplyFlightData copies provider-supplied fields onto a tracked flight,
+// keeping board details like the note, stage and crew count
+func ApplyFlightData(dst *Flight, src Flight) {
+	dst.FlightNumber = src.FlightNumber
+	dst.Airline = src.Airline
+	dst.Status = src.Status
+	dst.ScheduledArrival = src.ScheduledArrival
+	dst.ExpectedArrival = src.ExpectedArrival
+	dst.Delay = src.Delay
+	dst.IsDelayed = src.IsDelayed
+	dst.SortTime = src.SortTime
+	dst.Terminal = src.Terminal
+	dst.Gate = src.Gate
+	dst.BaggageClaim = src.BaggageClaim
+	dst.Origin = src.Origin
+	dst.OriginCity = src.OriginCity
+	dst.Destination = src.Destination
+	dst.DestinationCity = src.DestinationCity
+	dst.AircraftType = src.AircraftType
+	dst.Registration = src.Registration
+	dst.Source = src.Source
+}
+
+// RecordGateChange notes when a refresh moved a flight to a different
+// terminal or gate, so the board can point it out to drivers. A gate being
+// announced for the first time isn't a change.
+func RecordGateChange(before Flight, after *Flight, at time.Time) {
+	if before.Terminal == "" && before.Gate == "" {
+		return
+	}
+	if before.Terminal == after.Terminal && before.Gate == after.Gate {
+		return
+	}
+	after.PreviousGate = before.GateLabel()
+	after.GateChangedAt = at
+}
+
+// GateLabel returns the terminal and gate for display, e.g. "Terminal B,
+// Gate B12", or "" if neither is known
+func (f Flight) GateLabel() string {
+	switch {
+	case f.Terminal != "" && f.Gate != "":
+		return "Terminal " + f.Terminal + ", Gate " + f.Gate
+	case f.Gate != "":
+		return "Gate " + f.Gate
+	case f.Terminal != "":
+		return "Terminal " + f.Terminal
+	}
+	return ""
+}
+
+// LeaveBy returns when the shuttle must leave the hotel to meet the flight,
+// or the zero time if the flight has no arrival time
+func (f Flight) LeaveBy(drive time.Duration) time.Time {
+	if f.SortTime.IsZero() {
+		return time.Time{}
+	}
+	return f.SortTime.Add(-drive)
+}
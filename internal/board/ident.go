@@ -0,0 +1,125 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// airlineCodes maps the ICAO codes of airlines that fly crews in to their
+// IATA codes. Flight numbers are kept in IATA form on the board since that's
+// what's printed on crew schedules.
+var airlineCodes = map[string]string{
+	"AAL": "AA", // American Airlines
+	"ACA": "AC", // Air Canada
+	"AFR": "AF", // Air France
+	"AMX": "AM", // Aeromexico
+	"ASA": "AS", // Alaska Airlines
+	"AAY": "G4", // Allegiant Air
+	"BAW": "BA", // British Airways
+	"CPZ": "CP", // Compass Airlines
+	"DAL": "DL", // Delta Air Lines
+	"DLH": "LH", // Lufthansa
+	"EDV": "9E", // Endeavor Air
+	"ENY": "MQ", // Envoy Air
+	"FFT": "F9", // Frontier Airlines
+	"HAL": "HA", // Hawaiian Airlines
+	"ICE": "FI", // Icelandair
+	"JBU": "B6", // JetBlue
+	"JIA": "OH", // PSA Airlines
+	"NKS": "NK", // Spirit Airlines
+	"QXE": "QX", // Horizon Air
+	"RPA": "YX", // Republic Airways
+	"SCX": "SY", // Sun Country Airlines
+	"SKW": "OO", // SkyWest Airlines
+	"SWA": "WN", // Southwest Airlines
+	"UAL": "UA", // United Airlines
+	"VOI": "Y4", // Volaris
+	"WJA": "WS", // WestJet
+}
+
+// iataAirlines maps IATA codes back to ICAO for the lookups that want them
+var iataAirlines = func() map[string]string {
+	codes := map[string]string{}
+	for icao, iata := range airlineCodes {
+		codes[iata] = icao
+	}
+	return codes
+}()
+
+// NormalizeFlightNumber turns a flight number typed any common way into the
+// form the board uses: "aa 100", "AAL100" and "AA0100" all become "AA100".
+// Numbers for airlines it doesn't know only have spaces and dashes removed.
+func NormalizeFlightNumber(input string) (string, error) {
+	compact := strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '-' {
+			return -1
+		}
+		return r
+	}, input))
+	if compact == "" {
+		return "", fmt.Errorf("Flight number is required")
+	}
+
+	airline, number, ok := splitFlightNumber(compact)
+	if !ok {
+		if _, known := airlineCodes[compact]; known || iataAirlines[compact] != "" {
+			return "", fmt.Errorf("Flight number %q has no flight number after the airline", input)
+		}
+		return compact, nil
+	}
+	if iata, ok := airlineCodes[airline]; ok {
+		airline = iata
+	} else if _, ok := iataAirlines[airline]; !ok {
+		return compact, nil
+	}
+
+	number = strings.TrimLeft(number, "0")
+	if number == "" || number[0] < '0' || number[0] > '9' {
+		return "", fmt.Errorf("Flight number %q has no flight number after the airline", input)
+	}
+	return airline + number, nil
+}
+
+// ICAOFlightNumber returns a normalized flight number with its airline's
+// ICAO code, e.g. "AA100" becomes "AAL100", or the number unchanged if the
+// airline isn't known
+func ICAOFlightNumber(flightNumber string) string {
+	airline, number, ok := splitFlightNumber(flightNumber)
+	if !ok {
+		return flightNumber
+	}
+	if icao, ok := iataAirlines[airline]; ok {
+		return icao + number
+	}
+	return flightNumber
+}
+
+// splitFlightNumber splits a compact flight number into its airline code and
+// number: a known three-letter ICAO code, or a two-character IATA code with
+// at least one letter
+func splitFlightNumber(compact string) (airline, number string, ok bool) {
+	if len(compact) > 3 {
+		if _, known := airlineCodes[compact[:3]]; known {
+			return compact[:3], compact[3:], true
+		}
+	}
+	if len(compact) > 2 && isAirlineCode(compact[:2]) {
+		return compact[:2], compact[2:], true
+	}
+	return "", "", false
+}
+
+// isAirlineCode reports whether code looks like an IATA airline code
+func isAirlineCode(code string) bool {
+	hasLetter := false
+	for _, r := range code {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
@@ -0,0 +1,45 @@
+package board
+
+import "testing"
+
+func TestNormalizeFlightNumber(t *testing.T) {
+	cases := map[string]string{
+		"AA100":    "AA100",
+		"aa 100":   "AA100",
+		" AA-100 ": "AA100",
+		"AAL100":   "AA100",
+		"aal 0100": "AA100",
+		"AA0100":   "AA100",
+		"UAL12":    "UA12",
+		"B6 0023":  "B623",
+		"JBU23":    "B623",
+		"F9 1201":  "F91201",
+		"ZZ0100":   "ZZ0100", // Unknown airline, left alone apart from spacing
+		"N123AB":   "N123AB", // Tail numbers aren't airline flights
+	}
+	for input, want := range cases {
+		got, err := NormalizeFlightNumber(input)
+		if err != nil || got != want {
+			t.Errorf("NormalizeFlightNumber(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "  ", "AA", "AAL", "AA000"} {
+		if got, err := NormalizeFlightNumber(input); err == nil {
+			t.Errorf("NormalizeFlightNumber(%q) = %q, expected an error", input, got)
+		}
+	}
+}
+
+func TestICAOFlightNumber(t *testing.T) {
+	cases := map[string]string{
+		"AA100":  "AAL100",
+		"WN1234": "SWA1234",
+		"ZZ100":  "ZZ100",
+	}
+	for input, want := range cases {
+		if got := ICAOFlightNumber(input); got != want {
+			t.Errorf("ICAOFlightNumber(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
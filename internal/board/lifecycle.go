@@ -0,0 +1,109 @@
+package board
+
+import (
+	"fmt"
+	"time"
+)
+
+// Operational stages a tracked flight moves through on the board
+const (
+	StagePending    = "pending"
+	StageDispatched = "dispatched"
+	StageWaiting    = "waiting"
+	StagePickedUp   = "picked_up"
+	StageCompleted  = "completed"
+	StageNoShow     = "no_show"
+)
+
+// stageLabels are the button and badge labels shown to staff
+var stageLabels = map[string]string{
+	StagePending:    "Pending",
+	StageDispatched: "Shuttle dispatched",
+	StageWaiting:    "Crew waiting",
+	StagePickedUp:   "Picked up",
+	StageCompleted:  "Dropped at hotel",
+	StageNoShow:     "No-show",
+}
+
+// stageTransitions lists the stages each stage may move to, in button order.
+// Finished flights can be reopened in case a stage was tapped by mistake.
+var stageTransitions = map[string][]string{
+	StagePending:    {StageDispatched, StageNoShow},
+	StageDispatched: {StageWaiting, StagePickedUp, StageNoShow},
+	StageWaiting:    {StagePickedUp, StageNoShow},
+	StagePickedUp:   {StageCompleted},
+	StageCompleted:  {StagePending},
+	StageNoShow:     {StagePending},
+}
+
+// StageOption is a transition offered as a button on the flight card
+type StageOption struct {
+	Stage string
+	Label string
+}
+
+// CurrentStage returns the flight's stage, treating flights without one as pending
+func (f Flight) CurrentStage() string {
+	if f.Stage == "" {
+		return StagePending
+	}
+	return f.Stage
+}
+
+// StageLabel returns the display label for the flight's current stage
+func (f Flight) StageLabel() string {
+	return stageLabels[f.CurrentStage()]
+}
+
+// IsDone reports whether the crew has been dropped off or did not show
+func (f Flight) IsDone() bool {
+	stage := f.CurrentStage()
+	return stage == StageCompleted || stage == StageNoShow
+}
+
+// NextStages returns the transitions available from the flight's current stage
+func (f Flight) NextStages() []StageOption {
+	var options []StageOption
+	for _, stage := range stageTransitions[f.CurrentStage()] {
+		label := stageLabels[stage]
+		if f.IsDone() && stage == StagePending {
+			label = "Reopen"
+		}
+		options = append(options, StageOption{Stage: stage, Label: label})
+	}
+	return options
+}
+
+// LastStageChange returns the most recent stage transition, or nil if the flight is still untouched
+func (f Flight) LastStageChange() *StageChange {
+	if len(f.StageHistory) == 0 {
+		return nil
+	}
+	return &f.StageHistory[len(f.StageHistory)-1]
+}
+
+// AdvanceStage moves a flight to a new stage and timestamps the transition
+func AdvanceStage(flight *Flight, to, username string, at time.Time) error {
+	from := flight.CurrentStage()
+	for _, allowed := range stageTransitions[from] {
+		if allowed == to {
+			flight.Stage = to
+			flight.StageHistory = append(flight.StageHistory, StageChange{
+				From:     from,
+				To:       to,
+				Time:     at,
+				Username: username,
+			})
+			return nil
+		}
+	}
+	return fmt.Errorf("Cannot move flight from %s to %s", stageLabels[from], stageLabelOrName(to))
+}
+
+// stageLabelOrName returns the label for a stage, or the raw name if it isn't a known stage
+func stageLabelOrName(stage string) string {
+	if label, ok := stageLabels[stage]; ok {
+		return label
+	}
+	return stage
+}
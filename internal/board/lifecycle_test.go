@@ -0,0 +1,81 @@
+package board
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvanceStageFullPickup(t *testing.T) {
+	flight := Flight{ID: 1, Stage: StagePending}
+	at := time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC)
+
+	for _, stage := range []string{StageDispatched, StageWaiting, StagePickedUp, StageCompleted} {
+		if err := AdvanceStage(&flight, stage, "valet", at); err != nil {
+			t.Fatalf("Transition to %s failed: %v", stage, err)
+		}
+		at = at.Add(10 * time.Minute)
+	}
+
+	if !flight.IsDone() {
+		t.Error("Completed flight should be done")
+	}
+	if len(flight.StageHistory) != 4 {
+		t.Fatalf("Expected 4 stage changes, got %d", len(flight.StageHistory))
+	}
+	last := flight.LastStageChange()
+	if last.From != StagePickedUp || last.To != StageCompleted || last.Username != "valet" {
+		t.Errorf("Unexpected last stage change: %+v", last)
+	}
+}
+
+func TestAdvanceStageRejectsSkippingAhead(t *testing.T) {
+	flight := Flight{ID: 1}
+
+	if err := AdvanceStage(&flight, StageCompleted, "valet", time.Now()); err == nil {
+		t.Error("Pending flight should not jump straight to completed")
+	}
+	if flight.CurrentStage() != StagePending {
+		t.Errorf("Rejected transition changed the stage to %s", flight.CurrentStage())
+	}
+	if len(flight.StageHistory) != 0 {
+		t.Error("Rejected transition should not be recorded")
+	}
+}
+
+func TestNoShowCanBeReopened(t *testing.T) {
+	flight := Flight{ID: 1, Stage: StageNoShow}
+
+	options := flight.NextStages()
+	if len(options) != 1 || options[0].Stage != StagePending || options[0].Label != "Reopen" {
+		t.Fatalf("Expected a single Reopen option, got %+v", options)
+	}
+	if err := AdvanceStage(&flight, StagePending, "desk", time.Now()); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if flight.IsDone() {
+		t.Error("Reopened flight should not be done")
+	}
+}
+
+func TestRecordGateChange(t *testing.T) {
+	at := time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC)
+	before := Flight{Terminal: "B", Gate: "B12"}
+
+	after := before
+	RecordGateChange(before, &after, at)
+	if after.PreviousGate != "" || !after.GateChangedAt.IsZero() {
+		t.Errorf("An unchanged gate shouldn't be recorded: %+v", after)
+	}
+
+	after.Gate = "B30"
+	RecordGateChange(before, &after, at)
+	if after.PreviousGate != "Terminal B, Gate B12" || !after.GateChangedAt.Equal(at) {
+		t.Errorf("Expected the move from B12 to be recorded, got %q at %v", after.PreviousGate, after.GateChangedAt)
+	}
+
+	first := Flight{Gate: "A4"}
+	RecordGateChange(Flight{}, &first, at)
+	if first.PreviousGate != "" {
+		t.Errorf("A first gate assignment isn't a change, got %q", first.PreviousGate)
+	}
+}
@@ -0,0 +1,166 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"shuttletracker/internal/storage"
+)
+
+// RecurringSchedule automatically puts a flight on the board on given weekdays
+type RecurringSchedule struct {
+	ID            int
+	FlightNumber  string
+	Weekdays      []time.Weekday
+	StartDate     string // First service date the schedule applies (YYYY-MM-DD)
+	EndDate       string // Last service date, or "" for no end
+	Type          string // "pickup", "dropoff", or "both"
+	CrewCount     int
+	Note          string // Note copied onto each created flight
+	CreatedBy     string
+	LastPopulated string // Last service date a flight was added for this schedule
+}
+
+// RunsOn reports whether the schedule applies to a service date (YYYY-MM-DD)
+func (s RecurringSchedule) RunsOn(date string) bool {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return false
+	}
+	if date < s.StartDate || (s.EndDate != "" && date > s.EndDate) {
+		return false
+	}
+	for _, weekday := range s.Weekdays {
+		if weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// WeekdayNames returns the schedule's days as short names (e.g. "Mon, Wed")
+func (s RecurringSchedule) WeekdayNames() string {
+	var names []string
+	for _, weekday := range s.Weekdays {
+		names = append(names, weekday.String()[:3])
+	}
+	return strings.Join(names, ", ")
+}
+
+// Schedules holds the recurring schedules, persisted to a JSON file
+type Schedules struct {
+	path string
+
+	mu     sync.Mutex
+	list   []RecurringSchedule
+	nextID int
+}
+
+// NewSchedules returns an empty set of schedules saved to path
+func NewSchedules(path string) *Schedules {
+	return &Schedules{path: path, list: []RecurringSchedule{}, nextID: 1}
+}
+
+// Load reads the recurring schedules from disk
+func (s *Schedules) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stored []RecurringSchedule
+	if err := storage.ReadJSON(s.path, &stored); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Failed to read schedules: %s", err.Error())
+	}
+
+	s.list = stored
+	s.nextID = 1
+	for _, schedule := range s.list {
+		if schedule.ID >= s.nextID {
+			s.nextID = schedule.ID + 1
+		}
+	}
+	return nil
+}
+
+// saveLocked writes the schedules to disk. The caller must hold s.mu.
+func (s *Schedules) saveLocked() error {
+	return storage.WriteJSON(s.path, s.list)
+}
+
+// List returns a copy of the schedules ordered by flight number
+func (s *Schedules) List() []RecurringSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sorted := make([]RecurringSchedule, len(s.list))
+	copy(sorted, s.list)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FlightNumber < sorted[j].FlightNumber
+	})
+	return sorted
+}
+
+// Add stores a new recurring schedule
+func (s *Schedules) Add(schedule RecurringSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule.ID = s.nextID
+	s.nextID++
+	s.list = append(s.list, schedule)
+	return s.saveLocked()
+}
+
+// Delete removes a recurring schedule. Flights it already put on the board stay.
+func (s *Schedules) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, schedule := range s.list {
+		if schedule.ID == id {
+			s.list = append(s.list[:i], s.list[i+1:]...)
+			return s.saveLocked()
+		}
+	}
+	return nil
+}
+
+// PruneEnded removes the schedules whose end date is before a service date
+// (YYYY-MM-DD), since they can't run again, and returns how many it removed
+func (s *Schedules) PruneEnded(date string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.list[:0]
+	for _, schedule := range s.list {
+		if schedule.EndDate == "" || schedule.EndDate >= date {
+			kept = append(kept, schedule)
+		}
+	}
+	pruned := len(s.list) - len(kept)
+	s.list = kept
+	if pruned == 0 {
+		return 0, nil
+	}
+	return pruned, s.saveLocked()
+}
+
+// MarkPopulated records that a schedule's flight was added for a service date
+func (s *Schedules) MarkPopulated(id int, date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.list {
+		if s.list[i].ID == id {
+			s.list[i].LastPopulated = date
+			return s.saveLocked()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package board
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleRunsOn(t *testing.T) {
+	schedule := RecurringSchedule{
+		FlightNumber: "AA100",
+		Weekdays:     []time.Weekday{time.Monday, time.Friday},
+		StartDate:    "2025-03-03",
+		EndDate:      "2025-03-31",
+	}
+
+	cases := map[string]bool{
+		"2025-03-03": true,  // Monday, first day
+		"2025-03-07": true,  // Friday
+		"2025-03-04": false, // Tuesday
+		"2025-02-28": false, // Friday before the start date
+		"2025-04-04": false, // Friday after the end date
+		"not-a-date": false,
+	}
+	for date, want := range cases {
+		if got := schedule.RunsOn(date); got != want {
+			t.Errorf("RunsOn(%s) = %v, want %v", date, got, want)
+		}
+	}
+
+	schedule.EndDate = ""
+	if !schedule.RunsOn("2026-01-02") {
+		t.Error("Schedule without an end date should keep running")
+	}
+}
+
+func TestPruneEndedSchedules(t *testing.T) {
+	schedules := NewSchedules(t.TempDir() + "/schedules.json")
+	schedules.Add(RecurringSchedule{FlightNumber: "AA100", StartDate: "2025-03-03", EndDate: "2025-03-03"})
+	schedules.Add(RecurringSchedule{FlightNumber: "DL300", StartDate: "2025-03-01"})
+	schedules.Add(RecurringSchedule{FlightNumber: "UA12", StartDate: "2025-03-01", EndDate: "2025-03-04"})
+
+	pruned, err := schedules.PruneEnded("2025-03-04")
+	if err != nil {
+		t.Fatalf("PruneEnded failed: %v", err)
+	}
+	list := schedules.List()
+	if pruned != 1 || len(list) != 2 || list[0].FlightNumber != "DL300" || list[1].FlightNumber != "UA12" {
+		t.Errorf("Expected only the ended one-off to go, removed %d leaving %+v", pruned, list)
+	}
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeOnlyMovesWhenTold(t *testing.T) {
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	if !fake.Now().Equal(start) {
+		t.Errorf("Expected %s, got %s", start, fake.Now())
+	}
+	fake.Advance(90 * time.Minute)
+	if want := start.Add(90 * time.Minute); !fake.Now().Equal(want) {
+		t.Errorf("Expected %s after advancing, got %s", want, fake.Now())
+	}
+	fake.Set(start)
+	if !fake.Now().Equal(start) {
+		t.Errorf("Expected %s after setting, got %s", start, fake.Now())
+	}
+}
+
+func TestFakeAfterFiresWhenAdvanced(t *testing.T) {
+	fake := NewFake(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+	fired := fake.After(time.Minute)
+
+	fake.Advance(30 * time.Second)
+	select {
+	case <-fired:
+		t.Fatal("After fired before its time")
+	default:
+	}
+	if fake.Waiters() != 1 {
+		t.Errorf("Expected one waiter, got %d", fake.Waiters())
+	}
+
+	fake.Advance(30 * time.Second)
+	select {
+	case <-fired:
+	default:
+		t.Fatal("After didn't fire once the time had passed")
+	}
+	if fake.Waiters() != 0 {
+		t.Errorf("Expected no waiters left, got %d", fake.Waiters())
+	}
+}
@@ -0,0 +1,463 @@
+// Package config loads the server settings from defaults, a YAML file, the
+// environment and command-line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the server reads at startup. Settings come from
+// the defaults below, then the YAML config file, then environment variables,
+// then command-line flags, each overriding the last.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Log      LogConfig      `yaml:"log"`
+	Provider ProviderConfig `yaml:"provider"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Service  ServiceConfig  `yaml:"service"`
+	UI       UIConfig       `yaml:"ui"`
+	Demo     DemoConfig     `yaml:"demo"`
+
+	location *time.Location // Loaded from Service.Timezone by validate
+}
+
+// ServerConfig covers the HTTP server and on-disk data
+type ServerConfig struct {
+	Addr            string        `yaml:"addr"`
+	DataDir         string        `yaml:"data_dir"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay"` // How long /readyz fails before requests stop being accepted
+	MetricsToken    string        `yaml:"metrics_token"`
+
+	// HTTPS is on when a cert and key are given or TLSSelfSigned is set
+	TLSCert          string `yaml:"tls_cert"`
+	TLSKey           string `yaml:"tls_key"`
+	TLSSelfSigned    bool   `yaml:"tls_self_signed"`    // Generate and keep a self-signed cert in the data directory
+	HTTPRedirectAddr string `yaml:"http_redirect_addr"` // Plain HTTP address that redirects to HTTPS, or "" for none
+}
+
+// TLSEnabled reports whether the server serves HTTPS
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCert != "" || s.TLSSelfSigned
+}
+
+// LogConfig covers structured logging
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// ProviderConfig covers the flight data API
+type ProviderConfig struct {
+	APIKey         string        `yaml:"api_key"`
+	PollInterval   time.Duration `yaml:"poll_interval"`
+	LookupInterval time.Duration `yaml:"lookup_interval"`
+	CacheTTL       time.Duration `yaml:"cache_ttl"`
+	Timeout        time.Duration `yaml:"timeout"`
+	HomeAirport    string        `yaml:"home_airport"` // Airport crews fly in and out of, for suggestions and the airport page
+	AirportTTL     time.Duration `yaml:"airport_ttl"`  // How long the airport's arrivals and departures are cached
+
+	// Public URL of /hooks/flightaware. When set, tracked flights get AeroAPI
+	// alerts posted there and are only polled as a fallback.
+	WebhookURL    string `yaml:"webhook_url"`
+	WebhookSecret string `yaml:"webhook_secret"` // Token alerts must carry in the URL
+
+	// A second JSON flight API tried when FlightAware is down or doesn't know
+	// a flight. FallbackURL has {flight}, {flight_icao} and {key} placeholders;
+	// failover is off when it's empty.
+	FallbackName      string        `yaml:"fallback_name"` // Recorded as the source of the flights it supplies
+	FallbackURL       string        `yaml:"fallback_url"`
+	FallbackAPIKey    string        `yaml:"fallback_api_key"`
+	FallbackKeyHeader string        `yaml:"fallback_key_header"` // Header to also send the key in, if any
+	FallbackFields    string        `yaml:"fallback_fields"`     // field=path pairs; empty uses the aviationstack layout
+	FailoverCooldown  time.Duration `yaml:"failover_cooldown"`   // How long an unavailable provider is skipped
+}
+
+// AuthConfig covers passwords and hashing for the built-in accounts
+type AuthConfig struct {
+	BcryptCost    int    `yaml:"bcrypt_cost"`
+	ValetPassword string `yaml:"valet_password"`
+	DeskPassword  string `yaml:"desk_password"`
+	DemoPassword  string `yaml:"demo_password"`
+}
+
+// ServiceConfig covers the hotel's service day
+type ServiceConfig struct {
+	Timezone     string `yaml:"timezone"`
+	RolloverTime string `yaml:"rollover_time"`
+	DriveMinutes int    `yaml:"drive_minutes"`
+}
+
+// UIConfig covers the browser pages
+type UIConfig struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	DevDir          string        `yaml:"dev_dir"` // Load templates and static files from this checkout on every request
+}
+
+// DemoConfig covers the flight simulator behind demo accounts
+type DemoConfig struct {
+	Seed  int `yaml:"seed"`  // Same seed, same itineraries
+	Speed int `yaml:"speed"` // How many times faster than real time demo flights play out
+}
+
+// Default returns the settings used when nothing overrides them
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":8080",
+			DataDir:         "data",
+			ShutdownTimeout: 20 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Provider: ProviderConfig{
+			PollInterval:   5 * time.Minute,
+			LookupInterval: 6 * time.Second,
+			CacheTTL:       time.Minute,
+			Timeout:        15 * time.Second,
+			HomeAirport:    "KDEN",
+			AirportTTL:     15 * time.Minute,
+
+			FallbackName:     "fallback",
+			FailoverCooldown: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			BcryptCost: bcrypt.DefaultCost,
+		},
+		Service: ServiceConfig{
+			Timezone:     "America/Denver",
+			RolloverTime: "03:00",
+			DriveMinutes: 20,
+		},
+		UI: UIConfig{
+			RefreshInterval: 5 * time.Minute,
+		},
+		Demo: DemoConfig{
+			Seed:  1,
+			Speed: 1,
+		},
+	}
+}
+
+// configField ties one setting to its environment variable and flag
+type configField struct {
+	Key    string // Path in the config file, e.g. server.addr
+	Env    string
+	Flag   string // Empty for secrets, which shouldn't show up in ps
+	Usage  string
+	Secret bool
+	Value  flag.Value
+}
+
+// fields lists every setting that can be overridden
+func (c *Config) fields() []configField {
+	return []configField{
+		{"server.addr", "LISTEN_ADDR", "addr", "address to listen on", false, (*stringValue)(&c.Server.Addr)},
+		{"server.data_dir", "DATA_DIR", "data-dir", "directory for archives, schedules and tokens", false, (*stringValue)(&c.Server.DataDir)},
+		{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT", "shutdown-timeout", "how long to let requests drain on shutdown", false, (*durationValue)(&c.Server.ShutdownTimeout)},
+		{"server.shutdown_delay", "SHUTDOWN_DELAY", "shutdown-delay", "how long to report not ready before draining, within the shutdown timeout", false, (*durationValue)(&c.Server.ShutdownDelay)},
+		{"server.metrics_token", "METRICS_TOKEN", "", "bearer token required by /metrics", true, (*stringValue)(&c.Server.MetricsToken)},
+		{"server.tls_cert", "TLS_CERT_FILE", "tls-cert", "certificate file for HTTPS", false, (*stringValue)(&c.Server.TLSCert)},
+		{"server.tls_key", "TLS_KEY_FILE", "tls-key", "private key file for HTTPS", false, (*stringValue)(&c.Server.TLSKey)},
+		{"server.tls_self_signed", "TLS_SELF_SIGNED", "tls-self-signed", "serve HTTPS with a generated self-signed cert", false, (*boolValue)(&c.Server.TLSSelfSigned)},
+		{"server.http_redirect_addr", "HTTP_REDIRECT_ADDR", "http-redirect-addr", "plain HTTP address that redirects to HTTPS", false, (*stringValue)(&c.Server.HTTPRedirectAddr)},
+		{"log.level", "LOG_LEVEL", "log-level", "debug, info, warn or error", false, (*stringValue)(&c.Log.Level)},
+		{"log.format", "LOG_FORMAT", "log-format", "text or json", false, (*stringValue)(&c.Log.Format)},
+		{"provider.api_key", "FLIGHTAWARE_API_KEY", "", "FlightAware AeroAPI key", true, (*stringValue)(&c.Provider.APIKey)},
+		{"provider.poll_interval", "POLL_INTERVAL", "poll-interval", "how often live flights are refreshed", false, (*durationValue)(&c.Provider.PollInterval)},
+		{"provider.lookup_interval", "IMPORT_LOOKUP_INTERVAL", "lookup-interval", "minimum gap between background lookups", false, (*durationValue)(&c.Provider.LookupInterval)},
+		{"provider.cache_ttl", "LOOKUP_CACHE_TTL", "cache-ttl", "how long flight lookups are cached", false, (*durationValue)(&c.Provider.CacheTTL)},
+		{"provider.timeout", "PROVIDER_TIMEOUT", "provider-timeout", "timeout for each flight API call", false, (*durationValue)(&c.Provider.Timeout)},
+		{"provider.home_airport", "HOME_AIRPORT", "home-airport", "airport code crews fly in and out of", false, (*stringValue)(&c.Provider.HomeAirport)},
+		{"provider.airport_ttl", "AIRPORT_TTL", "airport-ttl", "how long the airport's arrivals and departures are cached", false, (*durationValue)(&c.Provider.AirportTTL)},
+		{"provider.webhook_url", "FLIGHTAWARE_WEBHOOK_URL", "webhook-url", "public URL of /hooks/flightaware for AeroAPI alerts", false, (*stringValue)(&c.Provider.WebhookURL)},
+		{"provider.webhook_secret", "FLIGHTAWARE_WEBHOOK_SECRET", "", "token AeroAPI alerts must carry", true, (*stringValue)(&c.Provider.WebhookSecret)},
+		{"provider.fallback_name", "FALLBACK_NAME", "fallback-name", "name recorded on flights from the fallback provider", false, (*stringValue)(&c.Provider.FallbackName)},
+		{"provider.fallback_url", "FALLBACK_URL", "fallback-url", "URL of a fallback JSON flight API, with {flight} and {key} placeholders", false, (*stringValue)(&c.Provider.FallbackURL)},
+		{"provider.fallback_api_key", "FALLBACK_API_KEY", "", "key for the fallback flight API", true, (*stringValue)(&c.Provider.FallbackAPIKey)},
+		{"provider.fallback_key_header", "FALLBACK_KEY_HEADER", "fallback-key-header", "header to send the fallback API key in", false, (*stringValue)(&c.Provider.FallbackKeyHeader)},
+		{"provider.fallback_fields", "FALLBACK_FIELDS", "fallback-fields", "field=path pairs mapping the fallback API's JSON onto flights", false, (*stringValue)(&c.Provider.FallbackFields)},
+		{"provider.failover_cooldown", "FAILOVER_COOLDOWN", "failover-cooldown", "how long an unavailable flight provider is skipped", false, (*durationValue)(&c.Provider.FailoverCooldown)},
+		{"auth.bcrypt_cost", "BCRYPT_COST", "bcrypt-cost", "bcrypt cost for password hashes", false, (*intValue)(&c.Auth.BcryptCost)},
+		{"auth.valet_password", "VALET_PASSWORD", "", "password for the valet account", true, (*stringValue)(&c.Auth.ValetPassword)},
+		{"auth.desk_password", "DESK_PASSWORD", "", "password for the desk account", true, (*stringValue)(&c.Auth.DeskPassword)},
+		{"auth.demo_password", "DEMO_PASSWORD", "", "password for the demo account", true, (*stringValue)(&c.Auth.DemoPassword)},
+		{"service.timezone", "TIMEZONE", "timezone", "IANA time zone of the hotel", false, (*stringValue)(&c.Service.Timezone)},
+		{"service.rollover_time", "ROLLOVER_TIME", "rollover-time", "local time the board rolls over (HH:MM)", false, (*stringValue)(&c.Service.RolloverTime)},
+		{"service.drive_minutes", "DRIVE_MINUTES", "drive-minutes", "hotel to airport drive time in minutes", false, (*intValue)(&c.Service.DriveMinutes)},
+		{"ui.refresh_interval", "UI_REFRESH_INTERVAL", "refresh-interval", "how often the board reloads in the browser", false, (*durationValue)(&c.UI.RefreshInterval)},
+		{"ui.dev_dir", "DEV_DIR", "dev-dir", "source checkout to reload templates and static files from (development)", false, (*stringValue)(&c.UI.DevDir)},
+		{"demo.seed", "DEMO_SEED", "demo-seed", "seed for the demo flight simulator", false, (*intValue)(&c.Demo.Seed)},
+		{"demo.speed", "DEMO_SPEED", "demo-speed", "how many times faster than real time demo flights play out", false, (*intValue)(&c.Demo.Speed)},
+	}
+}
+
+// Load builds the config from defaults, the config file, the environment and
+// args. printConfig reports whether --print-config was given.
+func Load(args []string) (cfg Config, printConfig bool, err error) {
+	cfg = Default()
+
+	flags := flag.NewFlagSet("shuttletracker", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("SHUTTLE_CONFIG"), "path to a YAML config file")
+	flags.BoolVar(&printConfig, "print-config", false, "print the effective config with secrets redacted and exit")
+
+	// Flags are parsed into their own values first so they can be applied last
+	flagValues := map[string]*pendingFlag{}
+	for _, field := range cfg.fields() {
+		if field.Flag != "" {
+			_, isBool := field.Value.(*boolValue)
+			flagValues[field.Flag] = &pendingFlag{value: field.Value.String(), isBool: isBool}
+			flags.Var(flagValues[field.Flag], field.Flag, field.Usage+" (env "+field.Env+")")
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, false, err
+	}
+
+	fields := cfg.fields()
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		for _, field := range fields {
+			if field.Flag == f.Name {
+				if err := field.Value.Set(flagValues[f.Name].value); err != nil && flagErr == nil {
+					flagErr = fmt.Errorf("Invalid -%s: %s", f.Name, err.Error())
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return Config{}, false, flagErr
+	}
+
+	return cfg, printConfig, cfg.validate()
+}
+
+// loadFile reads settings from a YAML file, rejecting unknown keys so typos
+// don't go unnoticed
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read config file: %s", err.Error())
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("Invalid config file %s: %s", path, err.Error())
+	}
+	return nil
+}
+
+// applyEnv overrides settings from any environment variables that are set
+func (c *Config) applyEnv() error {
+	for _, field := range c.fields() {
+		value := os.Getenv(field.Env)
+		if value == "" {
+			continue
+		}
+		if err := field.Value.Set(value); err != nil {
+			return fmt.Errorf("Invalid %s (%s): %s", field.Env, field.Key, err.Error())
+		}
+	}
+	return nil
+}
+
+// validate checks every setting and loads the time zone
+func (c *Config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, _, addrErr := net.SplitHostPort(c.Server.Addr)
+	check(addrErr == nil, "server.addr %q must be host:port, e.g. :8080", c.Server.Addr)
+	check(c.Server.DataDir != "", "server.data_dir is required")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.ShutdownDelay >= 0 && c.Server.ShutdownDelay < c.Server.ShutdownTimeout, "server.shutdown_delay must be between 0 and server.shutdown_timeout")
+	check((c.Server.TLSCert == "") == (c.Server.TLSKey == ""), "server.tls_cert and server.tls_key must be set together")
+	check(c.Server.TLSCert == "" || !c.Server.TLSSelfSigned, "server.tls_self_signed can't be combined with server.tls_cert")
+	if c.Server.HTTPRedirectAddr != "" {
+		_, _, redirectErr := net.SplitHostPort(c.Server.HTTPRedirectAddr)
+		check(redirectErr == nil, "server.http_redirect_addr %q must be host:port, e.g. :80", c.Server.HTTPRedirectAddr)
+		check(c.Server.TLSEnabled(), "server.http_redirect_addr needs HTTPS to be enabled")
+	}
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level %q must be debug, info, warn or error", c.Log.Level)
+	format := strings.ToLower(c.Log.Format)
+	check(format == "text" || format == "json", "log.format %q must be text or json", c.Log.Format)
+
+	check(c.Provider.PollInterval > 0, "provider.poll_interval must be positive")
+	check(c.Provider.LookupInterval >= 0, "provider.lookup_interval can't be negative")
+	check(c.Provider.CacheTTL >= 0, "provider.cache_ttl can't be negative")
+	check(c.Provider.Timeout > 0, "provider.timeout must be positive")
+	check(c.Provider.AirportTTL >= 0, "provider.airport_ttl can't be negative")
+	if c.Provider.WebhookURL != "" {
+		webhookURL, urlErr := url.Parse(c.Provider.WebhookURL)
+		check(urlErr == nil && (webhookURL.Scheme == "https" || webhookURL.Scheme == "http") && webhookURL.Host != "",
+			"provider.webhook_url %q must be an absolute http(s) URL", c.Provider.WebhookURL)
+		check(c.Provider.WebhookSecret != "", "provider.webhook_url needs provider.webhook_secret to be set")
+	}
+	if c.Provider.FallbackURL != "" {
+		fallbackURL, urlErr := url.Parse(strings.NewReplacer("{flight}", "AA100", "{flight_icao}", "AAL100", "{key}", "key").Replace(c.Provider.FallbackURL))
+		check(urlErr == nil && (fallbackURL.Scheme == "https" || fallbackURL.Scheme == "http") && fallbackURL.Host != "",
+			"provider.fallback_url %q must be an absolute http(s) URL", c.Provider.FallbackURL)
+		check(strings.Contains(c.Provider.FallbackURL, "{flight}") || strings.Contains(c.Provider.FallbackURL, "{flight_icao}"),
+			"provider.fallback_url %q needs a {flight} or {flight_icao} placeholder", c.Provider.FallbackURL)
+		check(c.Provider.FallbackName != "", "provider.fallback_name can't be empty")
+	}
+	check(c.Provider.FailoverCooldown >= 0, "provider.failover_cooldown can't be negative")
+
+	check(c.Auth.BcryptCost >= bcrypt.MinCost && c.Auth.BcryptCost <= bcrypt.MaxCost,
+		"auth.bcrypt_cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+
+	location, err := time.LoadLocation(c.Service.Timezone)
+	check(err == nil && c.Service.Timezone != "", "service.timezone %q is not a known time zone", c.Service.Timezone)
+	if err == nil {
+		c.location = location
+	}
+	_, _, clockErr := ParseClock(c.Service.RolloverTime)
+	check(clockErr == nil, "service.rollover_time %q must be HH:MM", c.Service.RolloverTime)
+	check(c.Service.DriveMinutes >= 0, "service.drive_minutes can't be negative")
+
+	check(c.UI.RefreshInterval > 0, "ui.refresh_interval must be positive")
+	if c.UI.DevDir != "" {
+		_, statErr := os.Stat(filepath.Join(c.UI.DevDir, "internal", "web", "templates", "layout.html"))
+		check(statErr == nil, "ui.dev_dir %q must contain internal/web/templates/layout.html", c.UI.DevDir)
+	}
+
+	check(c.Demo.Speed >= 1 && c.Demo.Speed <= 120, "demo.speed must be between 1 and 120")
+
+	return errors.Join(errs...)
+}
+
+// Location returns the hotel's time zone
+func (c Config) Location() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// Redacted returns a copy of the config with every secret that is set masked
+func (c Config) Redacted() Config {
+	for _, field := range c.fields() {
+		if field.Secret && field.Value.String() != "" {
+			field.Value.Set("REDACTED")
+		}
+	}
+	return c
+}
+
+// Rollover returns the hour and minute the service day starts
+func (c Config) Rollover() (hour, minute int) {
+	hour, minute, _ = ParseClock(c.Service.RolloverTime)
+	return hour, minute
+}
+
+// ParseClock parses an "HH:MM" time of day
+func ParseClock(value string) (hour, minute int, err error) {
+	hourStr, minuteStr, ok := strings.Cut(value, ":")
+	if ok {
+		hour, err = strconv.Atoi(hourStr)
+	}
+	if ok && err == nil {
+		minute, err = strconv.Atoi(minuteStr)
+	}
+	if !ok || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("Invalid time of day %q (expected HH:MM)", value)
+	}
+	return hour, minute, nil
+}
+
+// Write prints the config as YAML with secrets redacted
+func Write(w io.Writer, c Config) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// stringValue, intValue, boolValue and durationValue let config fields be
+// set from strings in the environment and on the command line
+type (
+	stringValue   string
+	intValue      int
+	boolValue     bool
+	durationValue time.Duration
+)
+
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+func (v *stringValue) String() string     { return string(*v) }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%q is not a whole number", s)
+	}
+	*v = intValue(n)
+	return nil
+}
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%q is not true or false", s)
+	}
+	*v = boolValue(b)
+	return nil
+}
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+func (v *durationValue) Set(s string) error {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%q is not a duration like 30s or 5m", s)
+	}
+	*v = durationValue(d)
+	return nil
+}
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+// pendingFlag holds a flag's raw value until the file and environment are applied
+type pendingFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *pendingFlag) Set(s string) error { f.value = s; return nil }
+func (f *pendingFlag) String() string     { return f.value }
+func (f *pendingFlag) IsBoolFlag() bool   { return f.isBool }
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigDefaultsMatchPreviousBehavior(t *testing.T) {
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Defaults should be valid: %v", err)
+	}
+	if cfg.Server.Addr != ":8080" || cfg.Provider.PollInterval != 5*time.Minute || cfg.Service.Timezone != "America/Denver" {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+	if cfg.Location().String() != "America/Denver" {
+		t.Errorf("Expected the Denver time zone to be loaded, got %s", cfg.Location())
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  addr: ":9000"
+  data_dir: /srv/shuttle
+provider:
+  poll_interval: 2m
+service:
+  timezone: America/Chicago
+  drive_minutes: 35
+`)
+	t.Setenv("POLL_INTERVAL", "90s")
+	t.Setenv("LISTEN_ADDR", ":9100")
+
+	cfg, _, err := Load([]string{"-config", path, "-addr", ":9200"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.DataDir != "/srv/shuttle" || cfg.Service.DriveMinutes != 35 {
+		t.Errorf("File settings not applied: %+v", cfg)
+	}
+	if cfg.Provider.PollInterval != 90*time.Second {
+		t.Errorf("Environment should override the file, got poll interval %s", cfg.Provider.PollInterval)
+	}
+	if cfg.Server.Addr != ":9200" {
+		t.Errorf("Flags should override the environment, got addr %s", cfg.Server.Addr)
+	}
+	if cfg.Location().String() != "America/Chicago" {
+		t.Errorf("Expected Chicago time zone, got %s", cfg.Location())
+	}
+}
+
+func TestConfigRejectsUnknownKeysAndBadValues(t *testing.T) {
+	path := writeConfigFile(t, "server:\n  adress: \":9000\"\n")
+	if _, _, err := Load([]string{"-config", path}); err == nil {
+		t.Error("Expected a misspelled key to be rejected")
+	}
+
+	path = writeConfigFile(t, `
+service:
+  timezone: Mars/Olympus
+  rollover_time: "25:00"
+auth:
+  bcrypt_cost: 2
+`)
+	_, _, err := Load([]string{"-config", path})
+	if err == nil {
+		t.Fatal("Expected invalid settings to be rejected")
+	}
+	for _, want := range []string{"service.timezone", "service.rollover_time", "auth.bcrypt_cost"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+
+	t.Setenv("POLL_INTERVAL", "often")
+	if _, _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "POLL_INTERVAL") {
+		t.Errorf("Expected a bad environment value to be reported, got %v", err)
+	}
+}
+
+func TestPrintConfigRedactsSecrets(t *testing.T) {
+	t.Setenv("DESK_PASSWORD", "hunter2")
+	t.Setenv("FLIGHTAWARE_API_KEY", "abc123")
+
+	cfg, printConfig, err := Load([]string{"--print-config"})
+	if err != nil || !printConfig {
+		t.Fatalf("Expected --print-config to be recognised: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "abc123") {
+		t.Errorf("Secrets leaked into printed config:\n%s", out)
+	}
+	if !strings.Contains(out, "desk_password: REDACTED") || !strings.Contains(out, "poll_interval: 5m0s") {
+		t.Errorf("Unexpected printed config:\n%s", out)
+	}
+	if cfg.Auth.DeskPassword != "hunter2" {
+		t.Error("Redacting for output should not change the loaded config")
+	}
+}
+
+func TestTLSConfigValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"-tls-cert", "cert.pem"},
+		{"-tls-cert", "cert.pem", "-tls-key", "key.pem", "-tls-self-signed"},
+		{"-http-redirect-addr", ":80"},
+	} {
+		if _, _, err := Load(args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+
+	if _, _, err := Load([]string{"-tls-self-signed", "-http-redirect-addr", ":8081"}); err != nil {
+		t.Errorf("Expected self-signed HTTPS with a redirect to be valid: %v", err)
+	}
+}
+
+func TestShutdownDelayValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"-shutdown-delay", "-1s"},
+		{"-shutdown-delay", "20s"},
+		{"-shutdown-delay", "10s", "-shutdown-timeout", "5s"},
+	} {
+		if _, _, err := Load(args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+
+	cfg, _, err := Load([]string{"-shutdown-delay", "0s"})
+	if err != nil || cfg.Server.ShutdownDelay != 0 {
+		t.Errorf("Expected no delay to be valid, got %v and %v", cfg.Server.ShutdownDelay, err)
+	}
+}
+
+func TestWebhookConfigValidation(t *testing.T) {
+	const hook = "https://shuttle.example.com/hooks/flightaware"
+	if _, _, err := Load([]string{"-webhook-url", hook}); err == nil {
+		t.Error("Expected a webhook URL without a secret to be rejected")
+	}
+
+	t.Setenv("FLIGHTAWARE_WEBHOOK_SECRET", "s3cret")
+	if _, _, err := Load([]string{"-webhook-url", "/hooks/flightaware"}); err == nil {
+		t.Error("Expected a relative webhook URL to be rejected")
+	}
+	if _, _, err := Load([]string{"-webhook-url", hook}); err != nil {
+		t.Errorf("Expected a webhook URL with a secret to be valid: %v", err)
+	}
+}
+
+func TestFallbackConfigValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"-fallback-url", "api.example.com/flights/{flight}"},
+		{"-fallback-url", "https://api.example.com/flights"},
+		{"-failover-cooldown", "-1m"},
+	} {
+		if _, _, err := Load(args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+
+	cfg, _, err := Load([]string{"-fallback-url", "https://api.example.com/v1/flights?access_key={key}&flight_iata={flight}"})
+	if err != nil {
+		t.Fatalf("Expected a fallback URL with placeholders to be valid: %v", err)
+	}
+	if cfg.Provider.FallbackName != "fallback" || cfg.Provider.FailoverCooldown != 5*time.Minute {
+		t.Errorf("Unexpected fallback defaults %+v", cfg.Provider)
+	}
+}
+
+func TestParseClockRejectsInvalidTimes(t *testing.T) {
+	for _, value := range []string{"", "3", "24:00", "03:60", "ab:cd"} {
+		if _, _, err := ParseClock(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	hour, minute, err := ParseClock("04:15")
+	if err != nil || hour != 4 || minute != 15 {
+		t.Errorf("Expected 04:15, got %d:%d (%v)", hour, minute, err)
+	}
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHistogramExposition(t *testing.T) {
+	hist := NewHistogramVec("test_duration_seconds", "Test latency.", []float64{0.1, 1}, "route")
+	hist.Observe(0.05, "/")
+	hist.Observe(0.5, "/")
+	hist.Observe(3, "/")
+
+	var buf bytes.Buffer
+	hist.Write(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{route="/",le="0.1"} 1`,
+		`test_duration_seconds_bucket{route="/",le="1"} 2`,
+		`test_duration_seconds_bucket{route="/",le="+Inf"} 3`,
+		`test_duration_seconds_sum{route="/"} 3.55`,
+		`test_duration_seconds_count{route="/"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestCounterEscapesLabels(t *testing.T) {
+	counter := NewCounterVec("test_total", "Test counter.", "path")
+	counter.Inc(`say "hi"`)
+	counter.Inc(`say "hi"`)
+
+	var buf bytes.Buffer
+	counter.Write(&buf)
+	if !strings.Contains(buf.String(), `test_total{path="say \"hi\""} 2`) {
+		t.Errorf("Unexpected counter output:\n%s", buf.String())
+	}
+}
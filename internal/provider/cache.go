@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/metrics"
+)
+
+// cachedLookup is a provider result and when it was fetched
+type cachedLookup struct {
+	flight  board.Flight
+	fetched time.Time
+}
+
+// cachedFlights is an airport's arrivals or departures and when they were fetched
+type cachedFlights struct {
+	flights   []board.Flight
+	truncated bool
+	fetched   time.Time
+}
+
+// Cache keeps recent lookups by flight number, so adding, importing and
+// editing the same flight in quick succession costs one API call
+type Cache struct {
+	next  Provider
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]cachedLookup
+}
+
+// NewCache wraps a provider, reusing its results for ttl as measured by clk
+func NewCache(next Provider, ttl time.Duration, clk clock.Clock) *Cache {
+	return &Cache{next: next, ttl: ttl, clock: clk, entries: map[string]cachedLookup{}}
+}
+
+// Lookup returns a recent lookup for the flight if there is one, otherwise
+// asks the wrapped provider. Failed lookups are not cached.
+func (c *Cache) Lookup(flightNumber string) (board.Flight, error) {
+	return c.lookup(flightNumber, func() (board.Flight, error) {
+		return c.next.Lookup(flightNumber)
+	})
+}
+
+// LookupDay is Lookup for the flight arriving between from and to, cached
+// separately for each day
+func (c *Cache) LookupDay(flightNumber string, from, to time.Time) (board.Flight, error) {
+	return c.lookup(flightNumber+" "+from.Format(time.RFC3339), func() (board.Flight, error) {
+		return LookupDay(c.next, flightNumber, from, to)
+	})
+}
+
+// LookupDeparture is LookupDay for the flight leaving airport, cached apart
+// from its arrival
+func (c *Cache) LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	return c.lookup("departure "+airport+" "+flightNumber+" "+from.Format(time.RFC3339), func() (board.Flight, error) {
+		return LookupDeparture(c.next, flightNumber, airport, from, to)
+	})
+}
+
+// lookup returns the entry cached under key if it's recent, otherwise
+// fetches and caches it
+func (c *Cache) lookup(key string, fetch func() (board.Flight, error)) (board.Flight, error) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+
+	if found && c.clock.Now().Sub(entry.fetched) < c.ttl {
+		metrics.LookupCacheRequests.Inc("hit")
+		return entry.flight, nil
+	}
+	metrics.LookupCacheRequests.Inc("miss")
+
+	flight, err := fetch()
+	if err != nil {
+		return board.Flight{}, err
+	}
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	c.entries[key] = cachedLookup{flight: flight, fetched: now}
+	// Drop stale entries so the cache doesn't grow all day
+	for cachedKey, cached := range c.entries {
+		if now.Sub(cached.fetched) >= c.ttl {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.mu.Unlock()
+
+	return flight, nil
+}
+
+// AirportCache keeps each airport's arrivals and departures for a while,
+// since they're asked for on every keystroke in the add form and every visit
+// to the airport page but change slowly
+type AirportCache struct {
+	next  AirportLister
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]cachedFlights
+}
+
+// NewAirportCache wraps an airport lister, reusing its results for ttl as
+// measured by clk
+func NewAirportCache(next AirportLister, ttl time.Duration, clk clock.Clock) *AirportCache {
+	return &AirportCache{next: next, ttl: ttl, clock: clk, entries: map[string]cachedFlights{}}
+}
+
+// Arrivals returns a recent list for the airport and time range if there is
+// one, otherwise asks the wrapped lister. Failures are not cached.
+func (c *AirportCache) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return c.list("arrivals", airport, from, to, c.next.Arrivals)
+}
+
+// Departures is Arrivals for flights leaving the airport
+func (c *AirportCache) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return c.list("departures", airport, from, to, c.next.Departures)
+}
+
+func (c *AirportCache) list(kind, airport string, from, to time.Time, fetch func(string, time.Time, time.Time) ([]board.Flight, bool, error)) ([]board.Flight, bool, error) {
+	key := kind + " " + airport + " " + from.Format(time.RFC3339) + " " + to.Format(time.RFC3339)
+
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+
+	if found && c.clock.Now().Sub(entry.fetched) < c.ttl {
+		return entry.flights, entry.truncated, nil
+	}
+
+	flights, truncated, err := fetch(airport, from, to)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	for cachedKey, cached := range c.entries {
+		if now.Sub(cached.fetched) >= c.ttl {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.entries[key] = cachedFlights{flights: flights, truncated: truncated, fetched: now}
+	c.mu.Unlock()
+
+	return flights, truncated, nil
+}
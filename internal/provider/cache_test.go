@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/metrics"
+)
+
+// countingProvider counts lookups and fails for unknown flights
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Lookup(flightNumber string) (board.Flight, error) {
+	p.calls++
+	if flightNumber == "XX0" {
+		return board.Flight{}, fmt.Errorf("flight %s not found", flightNumber)
+	}
+	return board.Flight{FlightNumber: flightNumber, Airline: "American Airlines"}, nil
+}
+
+func (p *countingProvider) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	p.calls++
+	if airport == "XXXX" {
+		return nil, false, fmt.Errorf("airport %s not found", airport)
+	}
+	return []board.Flight{{FlightNumber: "AA100"}, {FlightNumber: "UA200"}}, false, nil
+}
+
+func (p *countingProvider) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	p.calls++
+	return []board.Flight{{FlightNumber: "DL300", Departing: true}}, true, nil
+}
+
+func TestCacheReusesRecentLookups(t *testing.T) {
+	next := &countingProvider{}
+	cache := NewCache(next, time.Minute, clock.NewFake(time.Now()))
+
+	for range 2 {
+		flight, err := cache.Lookup("AA100")
+		if err != nil || flight.Airline != "American Airlines" {
+			t.Fatalf("Unexpected lookup result %+v (%v)", flight, err)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("Expected the second lookup to come from the cache, got %d provider calls", next.calls)
+	}
+
+	var buf bytes.Buffer
+	metrics.LookupCacheRequests.Write(&buf)
+	if !strings.Contains(buf.String(), `shuttle_lookup_cache_requests_total{result="hit"}`) {
+		t.Errorf("Expected the cache hit to be counted:\n%s", buf.String())
+	}
+}
+
+func TestCacheSkipsFailuresAndExpiredEntries(t *testing.T) {
+	next := &countingProvider{}
+	fake := clock.NewFake(time.Now())
+	cache := NewCache(next, time.Minute, fake)
+
+	cache.Lookup("AA100")
+	fake.Advance(59 * time.Second)
+	cache.Lookup("AA100")
+	if next.calls != 1 {
+		t.Errorf("Expected an entry inside the TTL to be reused, got %d provider calls", next.calls)
+	}
+	fake.Advance(time.Second)
+	cache.Lookup("AA100")
+	if next.calls != 2 {
+		t.Errorf("Expected expired entries to be looked up again, got %d provider calls", next.calls)
+	}
+
+	next.calls = 0
+	cache.Lookup("XX0")
+	cache.Lookup("XX0")
+	if next.calls != 2 {
+		t.Errorf("Expected failed lookups not to be cached, got %d provider calls", next.calls)
+	}
+}
+
+func TestAirportCacheReusesRecentLists(t *testing.T) {
+	next := &countingProvider{}
+	fake := clock.NewFake(time.Now())
+	cache := NewAirportCache(next, 15*time.Minute, fake)
+	from := fake.Now()
+	to := from.Add(24 * time.Hour)
+
+	for range 2 {
+		flights, _, err := cache.Arrivals("KDEN", from, to)
+		if err != nil || len(flights) != 2 {
+			t.Fatalf("Unexpected arrivals %+v (%v)", flights, err)
+		}
+	}
+	cache.Arrivals("KDEN", to, to.Add(24*time.Hour))
+	if next.calls != 2 {
+		t.Errorf("Expected one call per time range, got %d", next.calls)
+	}
+
+	fake.Advance(15 * time.Minute)
+	cache.Arrivals("KDEN", from, to)
+	if next.calls != 3 {
+		t.Errorf("Expected an expired list to be fetched again, got %d calls", next.calls)
+	}
+
+	cache.Arrivals("XXXX", from, to)
+	cache.Arrivals("XXXX", from, to)
+	if next.calls != 5 {
+		t.Errorf("Expected failures not to be cached, got %d calls", next.calls)
+	}
+
+	// Departures are kept apart from arrivals for the same airport and range
+	if flights, _, _ := cache.Departures("KDEN", from, to); len(flights) != 1 || next.calls != 6 {
+		t.Errorf("Expected departures to be fetched separately, got %+v after %d calls", flights, next.calls)
+	}
+	if _, truncated, _ := cache.Departures("KDEN", from, to); !truncated || next.calls != 6 {
+		t.Errorf("Expected a cached list to stay marked as cut off, got %v after %d calls", truncated, next.calls)
+	}
+}
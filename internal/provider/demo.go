@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+// Demo simulates flights for demo accounts without calling an API. Each
+// flight number gets an itinerary drawn from the seed the first time it's
+// looked up, and later lookups report how far it has played out: scheduled,
+// en route, landed and at the gate, with some flights delayed along the way
+// and a few cancelled or diverted.
+type Demo struct {
+	location *time.Location
+	clock    clock.Clock
+	seed     uint64
+	speed    int // How many times faster than real time itineraries play out
+
+	mu      sync.Mutex
+	flights map[string]*demoFlight
+}
+
+// demoFlight is one simulated itinerary
+type demoFlight struct {
+	airline   string
+	departs   time.Time
+	scheduled time.Time   // Scheduled arrival
+	delays    []demoDelay // Announced in order, each adding to the last
+	outcome   string      // demoCancelled, demoDiverted or "" if the flight lands
+	outcomeAt time.Time
+
+	city         demoCity // Where it comes from, or goes to if departing
+	aircraft     string
+	registration string
+	terminal     string
+	gate         string
+	baggage      string
+	gateChange   *demoGateChange
+}
+
+// demoDelay is a delay announced partway through an itinerary
+type demoDelay struct {
+	at      time.Time
+	minutes int
+}
+
+// demoGateChange moves a simulated flight to another gate partway through
+type demoGateChange struct {
+	at   time.Time
+	gate string
+}
+
+const (
+	demoCancelled = "Cancelled"
+	demoDiverted  = "Diverted"
+
+	// demoTaxi is how long a simulated flight spends between landing and the gate
+	demoTaxi = 10 * time.Minute
+	// demoForget is how long after arriving an itinerary is kept, so a flight
+	// added again the next day gets a new one
+	demoForget = 12 * time.Hour
+)
+
+// demoAirlines are the airlines simulated flights fly with, by IATA code
+var demoAirlines = []struct{ code, name string }{
+	{"AA", "American Airlines"},
+	{"DL", "Delta Air Lines"},
+	{"UA", "United Airlines"},
+	{"WN", "Southwest Airlines"},
+	{"AS", "Alaska Airlines"},
+	{"F9", "Frontier Airlines"},
+}
+
+// demoCity is an airport simulated flights fly to and from
+type demoCity struct{ code, name string }
+
+var demoCities = []demoCity{
+	{"ATL", "Atlanta"}, {"BOS", "Boston"}, {"DFW", "Dallas-Fort Worth"}, {"IAH", "Houston"},
+	{"JFK", "New York"}, {"LAX", "Los Angeles"}, {"ORD", "Chicago"}, {"PHX", "Phoenix"},
+	{"SEA", "Seattle"}, {"SFO", "San Francisco"},
+}
+
+var demoAircraft = []string{"A320", "A321", "B737", "B738", "B739", "CRJ9", "E175"}
+
+// demoArrivals is how many flights the simulator lists into the airport each day
+const demoArrivals = 40
+
+// NewDemo returns a demo provider showing times in the hotel's time zone.
+// The same seed always produces the same itineraries, and speed compresses
+// them so a three-hour flight can play out in minutes. Delays stay in real
+// minutes so they read naturally on the board.
+func NewDemo(location *time.Location, clk clock.Clock, seed int, speed int) *Demo {
+	return &Demo{location: location, clock: clk, seed: uint64(seed), speed: max(speed, 1), flights: map[string]*demoFlight{}}
+}
+
+// Lookup reports a simulated flight's status at the current time
+func (d *Demo) Lookup(flightNumber string) (board.Flight, error) {
+	now := d.clock.Now()
+	return d.itinerary(flightNumber, now, now).at(flightNumber, now, d.scale(demoTaxi), d.location), nil
+}
+
+// LookupDeparture reports a simulated flight's status at the current time as
+// a departure from the hotel's airport
+func (d *Demo) LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	flight, err := d.Lookup(flightNumber)
+	if err != nil {
+		return board.Flight{}, err
+	}
+	return asDeparture(flight), nil
+}
+
+// asDeparture turns a simulated arrival into a departure, flying back to
+// where the arrival came from
+func asDeparture(flight board.Flight) board.Flight {
+	flight.Departing = true
+	flight.Destination, flight.DestinationCity = flight.Origin, flight.OriginCity
+	flight.Origin, flight.OriginCity, flight.BaggageClaim = "", "", ""
+	return flight
+}
+
+// itinerary returns the flight's itinerary, planning it from start if it
+// hasn't got one yet, and forgets itineraries long finished by now
+func (d *Demo) itinerary(flightNumber string, start, now time.Time) *demoFlight {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for number, flight := range d.flights {
+		if now.Sub(flight.scheduled) > demoForget {
+			delete(d.flights, number)
+		}
+	}
+	flight, ok := d.flights[flightNumber]
+	if !ok {
+		flight = d.plan(flightNumber, start)
+		d.flights[flightNumber] = flight
+	}
+	return flight
+}
+
+// scale shortens a stretch of an itinerary by the simulator's speed
+func (d *Demo) scale(duration time.Duration) time.Duration {
+	return duration / time.Duration(d.speed)
+}
+
+// plan draws an itinerary for flightNumber starting at now. Flights depart
+// 20-60 minutes later and land 2-4 hours later, 40% get a delay before
+// departure and 15% another en route, 5% are cancelled and 4% diverted.
+func (d *Demo) plan(flightNumber string, now time.Time) *demoFlight {
+	hash := fnv.New64a()
+	hash.Write([]byte(flightNumber))
+	rng := rand.New(rand.NewPCG(d.seed, hash.Sum64()))
+	minutes := func(lo, hi int) time.Duration {
+		return d.scale(time.Duration(lo+rng.IntN(hi-lo+1)) * time.Minute)
+	}
+
+	// Keep the airline that goes with the flight number when there is one
+	flight := &demoFlight{airline: demoAirlines[rng.IntN(len(demoAirlines))].name}
+	for _, airline := range demoAirlines {
+		if strings.HasPrefix(flightNumber, airline.code) {
+			flight.airline = airline.name
+		}
+	}
+	flight.departs = now.Add(minutes(20, 60))
+	flight.scheduled = now.Add(minutes(120, 240))
+
+	if rng.Float64() < 0.4 {
+		at := now.Add(time.Duration(rng.Int64N(int64(flight.departs.Sub(now)))))
+		flight.delays = append(flight.delays, demoDelay{at: at, minutes: 10 + rng.IntN(36)})
+	}
+	if rng.Float64() < 0.15 {
+		at := flight.departs.Add(time.Duration(rng.Int64N(int64(flight.scheduled.Sub(flight.departs)))))
+		flight.delays = append(flight.delays, demoDelay{at: at, minutes: 5 + rng.IntN(26)})
+	}
+
+	switch outcome := rng.Float64(); {
+	case outcome < 0.05:
+		flight.outcome = demoCancelled
+		flight.outcomeAt = now.Add(time.Duration(rng.Int64N(int64(flight.departs.Sub(now)))))
+	case outcome < 0.09:
+		flight.outcome = demoDiverted
+		flight.outcomeAt = flight.departs.Add(time.Duration(rng.Int64N(int64(flight.scheduled.Sub(flight.departs)))))
+	}
+
+	// Where it flies from and where to meet it, with one in five moving gate
+	flight.city = demoCities[rng.IntN(len(demoCities))]
+	flight.aircraft = demoAircraft[rng.IntN(len(demoAircraft))]
+	flight.registration = fmt.Sprintf("N%d%c%c", 100+rng.IntN(900), 'A'+rng.IntN(26), 'A'+rng.IntN(26))
+	flight.terminal = string(rune('A' + rng.IntN(3)))
+	flight.gate = fmt.Sprintf("%s%d", flight.terminal, 1+rng.IntN(60))
+	flight.baggage = fmt.Sprint(1 + rng.IntN(20))
+	if rng.Float64() < 0.2 {
+		at := now.Add(time.Duration(rng.Int64N(int64(flight.scheduled.Sub(now)))))
+		flight.gateChange = &demoGateChange{at: at, gate: fmt.Sprintf("%s%d", flight.terminal, 1+rng.IntN(60))}
+	}
+	return flight
+}
+
+// Arrivals lists simulated flights due between from and to. Each day's
+// flights are drawn from the seed and the date, so a day's list is the same
+// however the range is sliced, and each one plays out like any other demo
+// flight once added.
+func (d *Demo) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return d.airportFlights(from, to, false), false, nil
+}
+
+// Departures lists simulated flights leaving between from and to, drawn the
+// same way as Arrivals
+func (d *Demo) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return d.airportFlights(from, to, true), false, nil
+}
+
+// airportFlights lists the simulated arrivals or departures between from
+// and to, taken from the schedule of each day the range touches
+func (d *Demo) airportFlights(from, to time.Time, departing bool) []board.Flight {
+	now := d.clock.Now()
+	local := from.In(d.location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, d.location)
+
+	var flights []board.Flight
+	seen := map[string]bool{}
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, listed := range d.daySchedule(day, departing) {
+			if seen[listed.number] {
+				continue
+			}
+			seen[listed.number] = true
+
+			flight := d.itinerary(listed.number, listed.start, now).at(listed.number, now, d.scale(demoTaxi), d.location)
+			if departing {
+				flight = asDeparture(flight)
+			}
+			if !flight.SortTime.Before(from) && flight.SortTime.Before(to) {
+				flights = append(flights, flight)
+			}
+		}
+	}
+	sort.Slice(flights, func(i, j int) bool {
+		return flights[i].SortTime.Before(flights[j].SortTime)
+	})
+	return flights
+}
+
+// demoListing is a flight on a day's simulated schedule and when its
+// itinerary starts
+type demoListing struct {
+	number string
+	start  time.Time
+}
+
+// daySchedule draws the flights listed on a day from the seed and the date.
+// Itineraries start between 4 AM and 10 PM, so they land through the day.
+func (d *Demo) daySchedule(day time.Time, departing bool) []demoListing {
+	hash := fnv.New64a()
+	hash.Write([]byte(day.Format("2006-01-02")))
+	if departing {
+		hash.Write([]byte(" departures"))
+	}
+	rng := rand.New(rand.NewPCG(d.seed, hash.Sum64()))
+
+	listings := make([]demoListing, 0, demoArrivals)
+	for range demoArrivals {
+		airline := demoAirlines[rng.IntN(len(demoAirlines))]
+		listings = append(listings, demoListing{
+			number: fmt.Sprintf("%s%d", airline.code, 100+rng.IntN(2900)),
+			start:  day.Add(4*time.Hour + time.Duration(rng.IntN(18*60))*time.Minute),
+		})
+	}
+	return listings
+}
+
+// at reports the itinerary as it stands at now
+func (f *demoFlight) at(flightNumber string, now time.Time, taxi time.Duration, location *time.Location) board.Flight {
+	delay := 0
+	for _, announced := range f.delays {
+		if !now.Before(announced.at) {
+			delay += announced.minutes
+		}
+	}
+	expected := f.scheduled.Add(time.Duration(delay) * time.Minute)
+
+	var status string
+	switch {
+	case f.outcome != "" && !now.Before(f.outcomeAt):
+		status = f.outcome
+	case now.Before(f.departs):
+		status = "Scheduled"
+	case now.Before(expected) && delay > 0:
+		status = "En Route / Delayed"
+	case now.Before(expected):
+		status = "En Route / On Time"
+	case now.Before(expected.Add(taxi)):
+		status = "Landed / Taxiing"
+	default:
+		status = "Arrived / Gate Arrival"
+	}
+
+	gate := f.gate
+	if f.gateChange != nil && !now.Before(f.gateChange.at) {
+		gate = f.gateChange.gate
+	}
+	// Bags are only assigned a carousel once the flight is down
+	baggage := ""
+	if !now.Before(expected) && f.outcome == "" {
+		baggage = f.baggage
+	}
+
+	return board.Flight{
+		FlightNumber:     flightNumber,
+		Airline:          f.airline,
+		Status:           status,
+		ScheduledArrival: f.scheduled.In(location).Format("3:04 PM"),
+		ExpectedArrival:  expected.In(location).Format("3:04 PM"),
+		Delay:            delay,
+		IsDelayed:        delay > 0,
+		SortTime:         expected.In(location),
+		Terminal:         f.terminal,
+		Gate:             gate,
+		BaggageClaim:     baggage,
+		Origin:           f.city.code,
+		OriginCity:       f.city.name,
+		AircraftType:     f.aircraft,
+		Registration:     f.registration,
+		Source:           "demo",
+	}
+}
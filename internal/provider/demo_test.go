@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+var demoStart = func() time.Time {
+	denver, _ := time.LoadLocation("America/Denver")
+	return time.Date(2025, 3, 3, 10, 0, 0, 0, denver)
+}()
+
+// demoStages orders the statuses a simulated flight moves through
+var demoStages = []string{"Scheduled", "En Route", "Landed", "Arrived", demoCancelled, demoDiverted}
+
+func demoStage(status string) int {
+	for i, stage := range demoStages {
+		if strings.HasPrefix(status, stage) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDemoSameSeedSameItineraries(t *testing.T) {
+	first := NewDemo(demoStart.Location(), clock.NewFake(demoStart), 7, 1)
+	second := NewDemo(demoStart.Location(), clock.NewFake(demoStart), 7, 1)
+	other := NewDemo(demoStart.Location(), clock.NewFake(demoStart), 8, 1)
+
+	differs := false
+	for i := range 20 {
+		number := fmt.Sprintf("AA%d", 100+i)
+		a, _ := first.Lookup(number)
+		b, _ := second.Lookup(number)
+		c, _ := other.Lookup(number)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("%s: expected the same itinerary from the same seed, got %+v and %+v", number, a, b)
+		}
+		if !reflect.DeepEqual(a, c) {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("Expected a different seed to give different itineraries")
+	}
+}
+
+func TestDemoFlightsPlayOutOverTime(t *testing.T) {
+	fake := clock.NewFake(demoStart)
+	demo := NewDemo(demoStart.Location(), fake, 1, 1)
+
+	const flights = 200
+	stages := make([]int, flights)
+	delayed, cancelled, diverted := 0, 0, 0
+	for i := range flights {
+		flight, _ := demo.Lookup(fmt.Sprintf("UA%d", i))
+		if flight.Status != "Scheduled" && flight.Status != demoCancelled {
+			t.Fatalf("Expected a new flight to be scheduled, got %q", flight.Status)
+		}
+		scheduled := flight.SortTime.Add(-time.Duration(flight.Delay) * time.Minute)
+		if scheduled.Before(demoStart.Add(2*time.Hour)) || scheduled.After(demoStart.Add(4*time.Hour)) {
+			t.Errorf("Expected an arrival 2-4 hours out, got %s", flight.ScheduledArrival)
+		}
+	}
+
+	// Statuses only move forward, and every flight has finished after six hours
+	for range 6 * 60 / 5 {
+		fake.Advance(5 * time.Minute)
+		for i := range flights {
+			flight, _ := demo.Lookup(fmt.Sprintf("UA%d", i))
+			stage := demoStage(flight.Status)
+			if stage < stages[i] {
+				t.Fatalf("UA%d went back from %s to %s", i, demoStages[stages[i]], flight.Status)
+			}
+			stages[i] = stage
+			if flight.IsDelayed && flight.Delay <= 0 {
+				t.Errorf("UA%d is delayed by %d minutes", i, flight.Delay)
+			}
+		}
+	}
+	for i := range flights {
+		flight, _ := demo.Lookup(fmt.Sprintf("UA%d", i))
+		switch flight.Status {
+		case "Arrived / Gate Arrival":
+		case demoCancelled:
+			cancelled++
+		case demoDiverted:
+			diverted++
+		default:
+			t.Errorf("UA%d still %q after six hours", i, flight.Status)
+		}
+		if flight.IsDelayed {
+			delayed++
+		}
+	}
+	if delayed == 0 || cancelled == 0 || diverted == 0 {
+		t.Errorf("Expected some delays, cancellations and diversions, got %d, %d and %d", delayed, cancelled, diverted)
+	}
+}
+
+func TestDemoSpeedCompressesItineraries(t *testing.T) {
+	fake := clock.NewFake(demoStart)
+	demo := NewDemo(demoStart.Location(), fake, 1, 12)
+
+	flight, _ := demo.Lookup("DL200")
+	if flight.SortTime.After(demoStart.Add(20*time.Minute + time.Duration(flight.Delay)*time.Minute)) {
+		t.Errorf("Expected a 12x flight to land within 20 minutes plus its delay, got %s", flight.ExpectedArrival)
+	}
+
+	fake.Advance(2 * time.Hour)
+	flight, _ = demo.Lookup("DL200")
+	if stage := demoStage(flight.Status); stage < demoStage("Arrived") {
+		t.Errorf("Expected the flight to be over after two hours at 12x, got %q", flight.Status)
+	}
+}
+
+func TestDemoAirportListFollowsTheDay(t *testing.T) {
+	numbers := func(flights []board.Flight) []string {
+		var listed []string
+		for _, flight := range flights {
+			listed = append(listed, flight.FlightNumber)
+		}
+		return listed
+	}
+	demo := NewDemo(demoStart.Location(), clock.NewFake(demoStart), 7, 1)
+	whole, _, _ := demo.Arrivals("DEN", demoStart, demoStart.Add(4*time.Hour))
+
+	// A fresh simulator asked for the same hours in 15-minute slices lists
+	// the same flights
+	sliced := NewDemo(demoStart.Location(), clock.NewFake(demoStart), 7, 1)
+	var slices []board.Flight
+	for from := demoStart; from.Before(demoStart.Add(4 * time.Hour)); from = from.Add(15 * time.Minute) {
+		window, _, _ := sliced.Arrivals("DEN", from, from.Add(15*time.Minute))
+		slices = append(slices, window...)
+	}
+	if len(whole) == 0 || !reflect.DeepEqual(numbers(whole), numbers(slices)) {
+		t.Errorf("Expected the same flights however the range is sliced, got %v and %v", numbers(whole), numbers(slices))
+	}
+
+	// Flights listed for the evening land in the evening, not 2-4 hours from now
+	evening := demoStart.Add(8 * time.Hour)
+	later, _, _ := demo.Arrivals("DEN", evening, evening.Add(2*time.Hour))
+	if len(later) == 0 {
+		t.Fatal("Expected flights listed for the evening")
+	}
+	for _, flight := range later {
+		if flight.SortTime.Before(evening) || !flight.SortTime.Before(evening.Add(2*time.Hour)) {
+			t.Errorf("Expected %s to land in the evening window, got %s", flight.FlightNumber, flight.SortTime)
+		}
+	}
+}
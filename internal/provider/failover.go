@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+// Source is one provider in a failover chain
+type Source struct {
+	Name     string // Shown in logs and recorded on flights that don't name their source
+	Provider Provider
+}
+
+// Failover tries its sources in priority order. A source that is down or out
+// of quota is skipped for a cooldown so lookups don't wait on it every time;
+// other failures, like a flight one API doesn't know, just move on to the
+// next source.
+type Failover struct {
+	sources  []Source
+	cooldown time.Duration
+	clock    clock.Clock
+
+	mu        sync.Mutex
+	downUntil map[string]time.Time
+}
+
+// NewFailover returns a provider that tries sources in order, skipping one
+// for cooldown after it turns out to be unavailable
+func NewFailover(sources []Source, cooldown time.Duration, clk clock.Clock) *Failover {
+	return &Failover{sources: sources, cooldown: cooldown, clock: clk, downUntil: map[string]time.Time{}}
+}
+
+// Lookup returns the flight from the first source that has it, or the
+// highest-priority source's error if none does
+func (f *Failover) Lookup(flightNumber string) (board.Flight, error) {
+	return f.lookup(func(source Provider) (board.Flight, error) {
+		return source.Lookup(flightNumber)
+	})
+}
+
+// LookupDay returns the flight arriving between from and to from the first
+// source that has it
+func (f *Failover) LookupDay(flightNumber string, from, to time.Time) (board.Flight, error) {
+	return f.lookup(func(source Provider) (board.Flight, error) {
+		return LookupDay(source, flightNumber, from, to)
+	})
+}
+
+// LookupDeparture returns the flight leaving airport between from and to
+// from the first source that has it
+func (f *Failover) LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	return f.lookup(func(source Provider) (board.Flight, error) {
+		return LookupDeparture(source, flightNumber, airport, from, to)
+	})
+}
+
+// lookup tries each source in turn
+func (f *Failover) lookup(fetch func(Provider) (board.Flight, error)) (board.Flight, error) {
+	var firstErr error
+	for _, source := range f.ready() {
+		flight, err := fetch(source.Provider)
+		if err == nil {
+			if flight.Source == "" {
+				flight.Source = source.Name
+			}
+			return flight, nil
+		}
+		f.failed(source, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return board.Flight{}, firstErr
+}
+
+// Arrivals lists an airport's arrivals from the first source that can
+func (f *Failover) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return f.list(func(lister AirportLister) ([]board.Flight, bool, error) {
+		return lister.Arrivals(airport, from, to)
+	})
+}
+
+// Departures lists an airport's departures from the first source that can
+func (f *Failover) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return f.list(func(lister AirportLister) ([]board.Flight, bool, error) {
+		return lister.Departures(airport, from, to)
+	})
+}
+
+// list tries each source that lists airport flights in turn
+func (f *Failover) list(fetch func(AirportLister) ([]board.Flight, bool, error)) ([]board.Flight, bool, error) {
+	var firstErr error
+	for _, source := range f.ready() {
+		lister, ok := source.Provider.(AirportLister)
+		if !ok {
+			continue
+		}
+		flights, truncated, err := fetch(lister)
+		if err == nil {
+			return flights, truncated, nil
+		}
+		f.failed(source, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = fmt.Errorf("No flight provider can list airport flights")
+	}
+	return nil, false, firstErr
+}
+
+// RegisterAlert registers the alert with the first source that has alerts
+func (f *Failover) RegisterAlert(flightNumber, targetURL string) (string, error) {
+	alerter, err := f.alerter()
+	if err != nil {
+		return "", err
+	}
+	return alerter.RegisterAlert(flightNumber, targetURL)
+}
+
+// DeleteAlert deletes an alert registered with RegisterAlert
+func (f *Failover) DeleteAlert(alertID string) error {
+	alerter, err := f.alerter()
+	if err != nil {
+		return err
+	}
+	return alerter.DeleteAlert(alertID)
+}
+
+// ParseAlert reads an alert posted by the source RegisterAlert used
+func (f *Failover) ParseAlert(body []byte) (Alert, error) {
+	alerter, err := f.alerter()
+	if err != nil {
+		return Alert{}, err
+	}
+	return alerter.ParseAlert(body)
+}
+
+// alerter returns the first source with push alerts
+func (f *Failover) alerter() (Alerter, error) {
+	for _, source := range f.sources {
+		if alerter, ok := source.Provider.(Alerter); ok {
+			return alerter, nil
+		}
+	}
+	return nil, fmt.Errorf("No flight provider supports alerts")
+}
+
+// ready returns the sources to try: the ones not cooling down, or every
+// source if they all are, so a recovered API is noticed
+func (f *Failover) ready() []Source {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	var ready []Source
+	for _, source := range f.sources {
+		if !now.Before(f.downUntil[source.Name]) {
+			ready = append(ready, source)
+		}
+	}
+	if len(ready) == 0 {
+		return f.sources
+	}
+	return ready
+}
+
+// failed puts a source that turned out to be unavailable on cooldown
+func (f *Failover) failed(source Source, err error) {
+	if !IsUnavailable(err) {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.downUntil[source.Name] = f.clock.Now().Add(f.cooldown)
+	slog.Warn("flight provider unavailable, failing over", "provider", source.Name, "cooldown", f.cooldown, "error", err)
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+// scriptedProvider answers lookups with a fixed flight or error and counts calls
+type scriptedProvider struct {
+	err   error
+	calls int
+}
+
+func (p *scriptedProvider) Lookup(flightNumber string) (board.Flight, error) {
+	p.calls++
+	if p.err != nil {
+		return board.Flight{}, p.err
+	}
+	return board.Flight{FlightNumber: flightNumber, Status: "Scheduled"}, nil
+}
+
+func TestFailoverSkipsUnavailableProvider(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+	primary := &scriptedProvider{err: unavailable("Failed to connect to flight API")}
+	backup := &scriptedProvider{}
+	failover := NewFailover([]Source{{"flightaware", primary}, {"backup", backup}}, 5*time.Minute, fake)
+
+	flight, err := failover.Lookup("AA100")
+	if err != nil || flight.Source != "backup" {
+		t.Fatalf("Expected the backup to answer, got %+v, %v", flight, err)
+	}
+	failover.Lookup("AA100")
+	if primary.calls != 1 || backup.calls != 2 {
+		t.Errorf("Expected the primary to be skipped while cooling down, got %d and %d calls", primary.calls, backup.calls)
+	}
+
+	primary.err = nil
+	fake.Advance(5 * time.Minute)
+	if flight, _ := failover.Lookup("AA100"); flight.Source != "flightaware" {
+		t.Errorf("Expected the primary back after the cooldown, got %q", flight.Source)
+	}
+}
+
+func TestFailoverTriesNextForUnknownFlight(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+	primary := &scriptedProvider{err: fmt.Errorf("Flight not found")}
+	backup := &scriptedProvider{}
+	failover := NewFailover([]Source{{"flightaware", primary}, {"backup", backup}}, 5*time.Minute, fake)
+
+	if flight, err := failover.Lookup("XY1"); err != nil || flight.Source != "backup" {
+		t.Errorf("Expected the backup to find the flight, got %+v, %v", flight, err)
+	}
+	failover.Lookup("XY1")
+	if primary.calls != 2 {
+		t.Errorf("An unknown flight shouldn't put the primary on cooldown, got %d calls", primary.calls)
+	}
+
+	backup.err = unavailable("backup is unavailable: 503")
+	if _, err := failover.Lookup("XY1"); err == nil || err.Error() != "Flight not found" {
+		t.Errorf("Expected the primary's error when every source fails, got %v", err)
+	}
+}
+
+func TestFailoverCoolsDownRefusedKey(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden} {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(status), status)
+		}))
+		flightAware := NewFlightAware("key", time.Second, time.UTC)
+		flightAware.baseURL = api.URL
+
+		fake := clock.NewFake(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+		backup := &scriptedProvider{}
+		failover := NewFailover([]Source{{"flightaware", flightAware}, {"backup", backup}}, 5*time.Minute, fake)
+
+		failover.Lookup("AA100")
+		if ready := failover.ready(); len(ready) != 1 || ready[0].Name != "backup" {
+			t.Errorf("%d: expected FlightAware to be cooling down, got %v", status, ready)
+		}
+		api.Close()
+	}
+}
@@ -0,0 +1,487 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// flightAwareURL is the AeroAPI base URL
+const flightAwareURL = "https://aeroapi.flightaware.com/aeroapi"
+
+// FlightAware looks flights up with the FlightAware AeroAPI
+type FlightAware struct {
+	apiKey   string
+	baseURL  string
+	client   *http.Client
+	location *time.Location // Hotel time zone arrival times are shown in
+}
+
+// NewFlightAware returns a client that gives up on calls after timeout so a
+// hung provider can't hold up a request or shutdown
+func NewFlightAware(apiKey string, timeout time.Duration, location *time.Location) *FlightAware {
+	return &FlightAware{
+		apiKey:   apiKey,
+		baseURL:  flightAwareURL,
+		client:   &http.Client{Timeout: timeout},
+		location: location,
+	}
+}
+
+// flightAwareFlight is the part of an AeroAPI flight the board uses
+type flightAwareFlight struct {
+	Ident        string `json:"ident"`
+	IdentIata    string `json:"ident_iata"`
+	OperatorIata string `json:"operator_iata"`
+	Operator     string `json:"operator"`
+	Status       string `json:"status"`
+	ScheduledIn  string `json:"scheduled_in"`
+	EstimatedIn  string `json:"estimated_in"`
+	ActualIn     string `json:"actual_in"`
+	ScheduledOut string `json:"scheduled_out"`
+	EstimatedOut string `json:"estimated_out"`
+	ActualOut    string `json:"actual_out"`
+
+	TerminalOrigin      string             `json:"terminal_origin"`
+	GateOrigin          string             `json:"gate_origin"`
+	TerminalDestination string             `json:"terminal_destination"`
+	GateDestination     string             `json:"gate_destination"`
+	BaggageClaim        string             `json:"baggage_claim"`
+	Origin              flightAwareAirport `json:"origin"`
+	Destination         flightAwareAirport `json:"destination"`
+	AircraftType        string             `json:"aircraft_type"`
+	Registration        string             `json:"registration"`
+}
+
+// flightAwareAirport is the part of an AeroAPI airport the board uses
+type flightAwareAirport struct {
+	Code     string `json:"code"`
+	CodeIata string `json:"code_iata"`
+	City     string `json:"city"`
+}
+
+// code returns the airport's IATA code, or its ICAO code if it has none
+func (a flightAwareAirport) code() string {
+	if a.CodeIata != "" {
+		return a.CodeIata
+	}
+	return a.Code
+}
+
+// Lookup fetches real-time flight data from FlightAware API
+func (f *FlightAware) Lookup(flightNumber string) (flight board.Flight, err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", flightNumber, start, err)
+	}()
+
+	flights, err := f.instances(flightNumber, nil)
+	if err != nil {
+		return board.Flight{}, err
+	}
+	return f.toFlight(flights[0], false)
+}
+
+// LookupDay fetches the instance of a flight that is scheduled to arrive
+// between from and to. AeroAPI lists a flight number's recent and upcoming
+// instances, and for a daily flight the first may be yesterday's or
+// tomorrow's.
+func (f *FlightAware) LookupDay(flightNumber string, from, to time.Time) (flight board.Flight, err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", flightNumber, start, err)
+	}()
+
+	// AeroAPI filters by departure, so reach back far enough for overnight
+	// and long-haul flights arriving early in the day
+	query := url.Values{}
+	query.Set("start", from.Add(-24*time.Hour).UTC().Format(time.RFC3339))
+	query.Set("end", to.UTC().Format(time.RFC3339))
+	flights, err := f.instances(flightNumber, query)
+	if err != nil {
+		return board.Flight{}, err
+	}
+
+	for _, data := range flights {
+		arrival := data.ScheduledIn
+		if arrival == "" {
+			arrival = data.ScheduledOut
+		}
+		scheduled, err := time.Parse(time.RFC3339, arrival)
+		if err == nil && !scheduled.Before(from) && scheduled.Before(to) {
+			return f.toFlight(data, false)
+		}
+	}
+	return board.Flight{}, fmt.Errorf("Flight not found on %s", from.In(f.location).Format("Jan 2"))
+}
+
+// LookupDeparture fetches the instance of a flight that is scheduled to
+// leave airport between from and to, timed by its departure. A flight number
+// can have several legs a day, so the leg must start at airport.
+func (f *FlightAware) LookupDeparture(flightNumber, airport string, from, to time.Time) (flight board.Flight, err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", "departure "+flightNumber, start, err)
+	}()
+
+	query := url.Values{}
+	query.Set("start", from.UTC().Format(time.RFC3339))
+	query.Set("end", to.UTC().Format(time.RFC3339))
+	flights, err := f.instances(flightNumber, query)
+	if err != nil {
+		return board.Flight{}, err
+	}
+
+	for _, data := range flights {
+		if !strings.EqualFold(data.Origin.Code, airport) && !strings.EqualFold(data.Origin.CodeIata, airport) {
+			continue
+		}
+		scheduled, err := time.Parse(time.RFC3339, data.ScheduledOut)
+		if err == nil && !scheduled.Before(from) && scheduled.Before(to) {
+			return f.toFlight(data, true)
+		}
+	}
+	return board.Flight{}, fmt.Errorf("Flight not found leaving %s on %s", airport, from.In(f.location).Format("Jan 2"))
+}
+
+// instances fetches the instances AeroAPI has for a flight number
+func (f *FlightAware) instances(flightNumber string, query url.Values) ([]flightAwareFlight, error) {
+	// ICAO idents are unambiguous where IATA codes are sometimes shared
+	var result struct {
+		Flights []flightAwareFlight `json:"flights"`
+	}
+	if err := f.get("/flights/"+url.PathEscape(board.ICAOFlightNumber(flightNumber)), query, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Flights) == 0 {
+		return nil, fmt.Errorf("Flight not found")
+	}
+	return result.Flights, nil
+}
+
+// Arrivals lists the airline flights still due into an airport between from
+// and to
+func (f *FlightAware) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return f.airportFlights(airport, "arrivals", from, to)
+}
+
+// Departures lists the airline flights still due to leave an airport between
+// from and to
+func (f *FlightAware) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return f.airportFlights(airport, "departures", from, to)
+}
+
+// AeroAPI lists an airport's flights 15 to a page. airportPages is how many
+// pages each call asks for and airportCalls how many calls a list may take,
+// so a busy hub's day can't run up the bill.
+const (
+	airportPages = 10
+	airportCalls = 4
+)
+
+// airportFlights fetches an airport's scheduled arrivals or departures,
+// following the next page link until the list runs out or airportCalls is
+// reached
+func (f *FlightAware) airportFlights(airport, kind string, from, to time.Time) (flights []board.Flight, truncated bool, err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", kind+" "+airport, start, err)
+	}()
+
+	path := "/airports/" + url.PathEscape(airport) + "/flights/scheduled_" + kind
+	query := url.Values{
+		"start": {from.UTC().Format(time.RFC3339)},
+		"end":   {to.UTC().Format(time.RFC3339)},
+		"type":  {"Airline"},
+	}
+	for calls := 1; ; calls++ {
+		query.Set("max_pages", strconv.Itoa(airportPages))
+		var result struct {
+			ScheduledArrivals   []flightAwareFlight `json:"scheduled_arrivals"`
+			ScheduledDepartures []flightAwareFlight `json:"scheduled_departures"`
+			Links               struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		}
+		if err := f.get(path, query, &result); err != nil {
+			return nil, false, err
+		}
+
+		// Skip flights without a usable time rather than failing the list
+		for _, data := range result.ScheduledArrivals {
+			if flight, err := f.toFlight(data, false); err == nil {
+				flights = append(flights, flight)
+			}
+		}
+		for _, data := range result.ScheduledDepartures {
+			if flight, err := f.toFlight(data, true); err == nil {
+				flights = append(flights, flight)
+			}
+		}
+
+		if result.Links.Next == "" {
+			return flights, false, nil
+		}
+		if calls == airportCalls {
+			slog.Warn("airport list cut off", "airport", airport, "kind", kind, "flights", len(flights))
+			return flights, true, nil
+		}
+		// The next link is relative to the API base and carries a cursor
+		next, err := url.Parse(result.Links.Next)
+		if err != nil {
+			return nil, false, fmt.Errorf("Failed to parse API response: %s", err.Error())
+		}
+		path, query = next.Path, next.Query()
+	}
+}
+
+// get calls an AeroAPI endpoint and decodes the JSON response into result
+func (f *FlightAware) get(path string, query url.Values, result any) error {
+	resp, body, err := f.send("GET", path, query, nil)
+	if err != nil {
+		return err
+	}
+	if statusUnavailable(resp.StatusCode) {
+		return unavailable("Flight API is unavailable: %s", resp.Status)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("Failed to parse API response: %s", err.Error())
+	}
+	return nil
+}
+
+// send makes an AeroAPI call, with payload as the JSON body if it isn't nil,
+// and returns the response along with its body
+func (f *FlightAware) send(method, path string, query url.Values, payload any) (*http.Response, []byte, error) {
+	apiURL := f.baseURL + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to create request")
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, apiURL, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create request")
+	}
+
+	// FlightAware uses x-apikey header for authentication
+	req.Header.Set("x-apikey", f.apiKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, unavailable("Failed to connect to flight API")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, unavailable("Failed to read API response")
+	}
+	return resp, body, nil
+}
+
+// flightAwareEvents are the alert events that matter to the shuttle
+var flightAwareEvents = map[string]bool{
+	"filed":     true,
+	"departure": true,
+	"arrival":   true,
+	"diverted":  true,
+	"cancelled": true,
+}
+
+// RegisterAlert sets up an AeroAPI alert that posts the flight's changes to
+// targetURL and returns its ID
+func (f *FlightAware) RegisterAlert(flightNumber, targetURL string) (alertID string, err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", "alert "+flightNumber, start, err)
+	}()
+
+	payload := map[string]any{
+		"ident":      board.ICAOFlightNumber(flightNumber),
+		"target_url": targetURL,
+		"events":     flightAwareEvents,
+	}
+	resp, body, err := f.send("POST", "/alerts", nil, payload)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Flight API refused the alert: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// The new alert's ID is the last part of its Location, e.g. /alerts/1234
+	location := strings.TrimRight(resp.Header.Get("Location"), "/")
+	alertID = location[strings.LastIndex(location, "/")+1:]
+	if alertID == "" {
+		return "", fmt.Errorf("Flight API didn't return an alert ID")
+	}
+	return alertID, nil
+}
+
+// DeleteAlert stops an alert registered with RegisterAlert. An alert that's
+// already gone counts as deleted.
+func (f *FlightAware) DeleteAlert(alertID string) (err error) {
+	start := time.Now()
+	defer func() {
+		observe("flightaware", "alert "+alertID, start, err)
+	}()
+
+	resp, body, err := f.send("DELETE", "/alerts/"+url.PathEscape(alertID), nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("Flight API refused to delete the alert: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// flightAwareAlert is the part of an AeroAPI alert callback the board uses
+type flightAwareAlert struct {
+	AlertID   json.Number       `json:"alert_id"`
+	EventCode string            `json:"event_code"`
+	Flight    flightAwareFlight `json:"flight"`
+}
+
+// ParseAlert reads an AeroAPI alert callback. Diversions and cancellations
+// show in the status even if the flight data hasn't caught up yet.
+func (f *FlightAware) ParseAlert(body []byte) (Alert, error) {
+	var data flightAwareAlert
+	if err := json.Unmarshal(body, &data); err != nil {
+		return Alert{}, fmt.Errorf("Alert isn't valid JSON: %s", err.Error())
+	}
+	if data.Flight.Ident == "" && data.Flight.IdentIata == "" {
+		return Alert{}, fmt.Errorf("Alert has no flight")
+	}
+
+	event := strings.ToLower(data.EventCode)
+	flight := func(departing bool) (board.Flight, error) {
+		resolved, err := f.toFlight(data.Flight, departing)
+		if err != nil {
+			return board.Flight{}, err
+		}
+		status := strings.ToLower(resolved.Status)
+		if event == "diverted" && !strings.Contains(status, "divert") {
+			resolved.Status = "Diverted"
+		}
+		if event == "cancelled" && !strings.Contains(status, "cancel") {
+			resolved.Status = "Cancelled"
+		}
+		return resolved, nil
+	}
+
+	// Resolve once to check the flight has usable times and learn its number
+	resolved, err := flight(false)
+	if err != nil {
+		if resolved, err = flight(true); err != nil {
+			return Alert{}, err
+		}
+	}
+	return Alert{
+		AlertID:      data.AlertID.String(),
+		Event:        event,
+		FlightNumber: resolved.FlightNumber,
+		flight:       flight,
+	}, nil
+}
+
+// toFlight converts an AeroAPI flight to board data in the hotel's time
+// zone, timed and placed by its arrival, or its departure if departing
+func (f *FlightAware) toFlight(flightData flightAwareFlight, departing bool) (board.Flight, error) {
+	scheduled, estimated, actual := flightData.ScheduledIn, flightData.EstimatedIn, flightData.ActualIn
+	terminal, gate, baggage := flightData.TerminalDestination, flightData.GateDestination, flightData.BaggageClaim
+	if departing {
+		scheduled, estimated, actual = flightData.ScheduledOut, flightData.EstimatedOut, flightData.ActualOut
+		terminal, gate, baggage = flightData.TerminalOrigin, flightData.GateOrigin, ""
+	}
+
+	// Use most accurate time available (actual > estimated > scheduled)
+	arrivalTimeStr := scheduled
+	if estimated != "" {
+		arrivalTimeStr = estimated
+	}
+	if actual != "" {
+		arrivalTimeStr = actual
+	}
+
+	if arrivalTimeStr == "" {
+		return board.Flight{}, fmt.Errorf("Flight has no arrival time data available")
+	}
+
+	// Parse ISO 8601 time format
+	scheduledTime, parseErr := time.Parse(time.RFC3339, arrivalTimeStr)
+	if parseErr != nil {
+		return board.Flight{}, fmt.Errorf("Failed to parse time: %s", parseErr.Error())
+	}
+
+	// Convert to the hotel's local time
+	scheduledMT := scheduledTime.In(f.location)
+
+	// Calculate delay by comparing scheduled vs estimated
+	var delay int
+	if scheduled != "" && estimated != "" {
+		scheduledT, _ := time.Parse(time.RFC3339, scheduled)
+		estimatedT, _ := time.Parse(time.RFC3339, estimated)
+		delay = int(estimatedT.Sub(scheduledT).Minutes())
+	}
+
+	expectedMT := scheduledMT
+
+	// Prefer full airline name over IATA code
+	airlineName := flightData.Operator
+	if airlineName == "" {
+		airlineName = flightData.OperatorIata
+	}
+
+	// Show the IATA flight number staff know the flight by
+	ident := flightData.IdentIata
+	if ident == "" {
+		ident = flightData.Ident
+	}
+	flightNumber, err := board.NormalizeFlightNumber(ident)
+	if err != nil {
+		flightNumber = ident
+	}
+
+	return board.Flight{
+		FlightNumber:     flightNumber,
+		Airline:          airlineName,
+		Status:           flightData.Status,
+		ScheduledArrival: scheduledMT.Format("3:04 PM"),
+		ExpectedArrival:  expectedMT.Format("3:04 PM"),
+		Delay:            delay,
+		IsDelayed:        delay > 0,
+		SortTime:         expectedMT,
+		Departing:        departing,
+		Terminal:         terminal,
+		Gate:             gate,
+		BaggageClaim:     baggage,
+		Origin:           flightData.Origin.code(),
+		OriginCity:       flightData.Origin.City,
+		Destination:      flightData.Destination.code(),
+		DestinationCity:  flightData.Destination.City,
+		AircraftType:     flightData.AircraftType,
+		Registration:     flightData.Registration,
+		Source:           "flightaware",
+	}, nil
+}
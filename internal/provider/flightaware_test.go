@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestFlightAware points a client at a fake AeroAPI that answers with body
+// and records the last request path and query
+func newTestFlightAware(t *testing.T, body string, lastRequest *string) *FlightAware {
+	t.Helper()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*lastRequest = r.URL.RequestURI()
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(api.Close)
+
+	denver, _ := time.LoadLocation("America/Denver")
+	flightAware := NewFlightAware("key", time.Second, denver)
+	flightAware.baseURL = api.URL
+	return flightAware
+}
+
+func TestFlightAwareLookupUsesICAOIdent(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"flights": [{"ident": "AAL100", "ident_iata": "AA100", "operator": "American Airlines",
+		"status": "Scheduled", "scheduled_in": "2025-03-03T19:00:00Z", "estimated_in": "2025-03-03T19:20:00Z"}]}`, &request)
+
+	flight, err := flightAware.Lookup("AA100")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if request != "/flights/AAL100" {
+		t.Errorf("Expected the ICAO ident to be looked up, got %s", request)
+	}
+	if flight.FlightNumber != "AA100" || flight.ExpectedArrival != "12:20 PM" || flight.Delay != 20 {
+		t.Errorf("Unexpected flight %+v", flight)
+	}
+}
+
+func TestFlightAwareLookupDayPicksTheDaysInstance(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"flights": [
+		{"ident": "AAL100", "ident_iata": "AA100", "status": "Scheduled", "scheduled_in": "2025-03-04T19:00:00Z"},
+		{"ident": "AAL100", "ident_iata": "AA100", "status": "En Route", "scheduled_in": "2025-03-03T19:00:00Z"},
+		{"ident": "AAL100", "ident_iata": "AA100", "status": "Arrived", "scheduled_in": "2025-03-02T19:00:00Z"}]}`, &request)
+
+	from := time.Date(2025, 3, 3, 3, 0, 0, 0, flightAware.location)
+	flight, err := flightAware.LookupDay("AA100", from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("LookupDay failed: %v", err)
+	}
+	if flight.Status != "En Route" {
+		t.Errorf("Expected the instance arriving on Mar 3, got %+v", flight)
+	}
+	if !strings.Contains(request, "start=2025-03-02T10%3A00%3A00Z") || !strings.Contains(request, "end=2025-03-04T10%3A00%3A00Z") {
+		t.Errorf("Expected the lookup to be limited to the day, got %s", request)
+	}
+
+	later := from.AddDate(0, 0, 5)
+	if _, err := flightAware.LookupDay("AA100", later, later.AddDate(0, 0, 1)); err == nil {
+		t.Error("Expected no flight on a day without an instance")
+	}
+}
+
+func TestFlightAwareLookupDeparturePicksTheLegFromTheAirport(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"flights": [
+		{"ident": "DAL300", "ident_iata": "DL300", "status": "En Route", "origin": {"code": "KATL", "code_iata": "ATL"},
+		 "scheduled_out": "2025-03-03T14:00:00Z", "scheduled_in": "2025-03-03T17:00:00Z"},
+		{"ident": "DAL300", "ident_iata": "DL300", "status": "Scheduled", "origin": {"code": "KDEN", "code_iata": "DEN"},
+		 "scheduled_out": "2025-03-03T18:00:00Z", "estimated_out": "2025-03-03T18:10:00Z", "scheduled_in": "2025-03-03T21:00:00Z"}]}`, &request)
+
+	from := time.Date(2025, 3, 3, 3, 0, 0, 0, flightAware.location)
+	flight, err := flightAware.LookupDeparture("DL300", "KDEN", from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("LookupDeparture failed: %v", err)
+	}
+	if !flight.Departing || flight.Status != "Scheduled" || flight.ExpectedArrival != "11:10 AM" {
+		t.Errorf("Expected the leg leaving KDEN timed by its departure, got %+v", flight)
+	}
+	if !strings.HasPrefix(request, "/flights/DAL300?") || !strings.Contains(request, "start=2025-03-03T10%3A00%3A00Z") {
+		t.Errorf("Expected the lookup to be limited to the day, got %s", request)
+	}
+
+	if _, err := flightAware.LookupDeparture("DL300", "KSEA", from, from.AddDate(0, 0, 1)); err == nil {
+		t.Error("Expected no departure from an airport the flight doesn't leave")
+	}
+}
+
+func TestFlightAwareArrivals(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"scheduled_arrivals": [
+		{"ident": "UAL12", "operator": "United Airlines", "status": "En Route", "scheduled_in": "2025-03-03T18:00:00Z"},
+		{"ident": "SWA1234", "ident_iata": "WN1234", "operator": "Southwest Airlines", "status": "Scheduled", "scheduled_in": "2025-03-03T19:00:00Z"},
+		{"ident": "N123AB", "status": "Scheduled"}
+	]}`, &request)
+
+	from := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	flights, _, err := flightAware.Arrivals("KDEN", from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Arrivals failed: %v", err)
+	}
+	want := "/airports/KDEN/flights/scheduled_arrivals?end=2025-03-04T10%3A00%3A00Z&max_pages=10&start=2025-03-03T10%3A00%3A00Z&type=Airline"
+	if request != want {
+		t.Errorf("Unexpected request %s", request)
+	}
+	if len(flights) != 2 || flights[0].FlightNumber != "UA12" || flights[1].FlightNumber != "WN1234" || flights[0].ScheduledArrival != "11:00 AM" {
+		t.Errorf("Expected the two flights with arrival times in IATA form, got %+v", flights)
+	}
+}
+
+func TestFlightAwareArrivalsFollowNextPage(t *testing.T) {
+	var requests []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"scheduled_arrivals": [{"ident": "UAL12", "scheduled_in": "2025-03-03T18:00:00Z"}],
+				"links": {"next": "/airports/KDEN/flights/scheduled_arrivals?type=Airline&cursor=abc"}}`))
+			return
+		}
+		w.Write([]byte(`{"scheduled_arrivals": [{"ident": "DAL300", "scheduled_in": "2025-03-03T23:00:00Z"}], "links": null}`))
+	}))
+	t.Cleanup(api.Close)
+	flightAware := NewFlightAware("key", time.Second, time.UTC)
+	flightAware.baseURL = api.URL
+
+	from := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	flights, truncated, err := flightAware.Arrivals("KDEN", from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Arrivals failed: %v", err)
+	}
+	if len(flights) != 2 || flights[1].FlightNumber != "DL300" || truncated {
+		t.Errorf("Expected the whole list from both pages, got %+v (cut off %v)", flights, truncated)
+	}
+	if len(requests) != 2 || requests[1] != "/airports/KDEN/flights/scheduled_arrivals?cursor=abc&max_pages=10&type=Airline" {
+		t.Errorf("Expected the next page to be fetched from its link, got %v", requests)
+	}
+}
+
+func TestFlightAwareArrivalsStopPaging(t *testing.T) {
+	calls := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"scheduled_arrivals": [{"ident": "UAL12", "scheduled_in": "2025-03-03T18:00:00Z"}],
+			"links": {"next": "/airports/KDEN/flights/scheduled_arrivals?cursor=more"}}`))
+	}))
+	t.Cleanup(api.Close)
+	flightAware := NewFlightAware("key", time.Second, time.UTC)
+	flightAware.baseURL = api.URL
+
+	from := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	flights, truncated, err := flightAware.Arrivals("KDEN", from, from.Add(24*time.Hour))
+	if err != nil || calls != airportCalls || len(flights) != airportCalls || !truncated {
+		t.Errorf("Expected %d calls before the list was cut off, got %d calls, %d flights and cut off %v (%v)", airportCalls, calls, len(flights), truncated, err)
+	}
+}
+
+func TestFlightAwareDeparturesUseDepartureTimes(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"scheduled_departures": [
+		{"ident": "DAL300", "ident_iata": "DL300", "operator": "Delta Air Lines", "status": "Scheduled",
+		 "scheduled_out": "2025-03-03T20:00:00Z", "estimated_out": "2025-03-03T20:15:00Z", "scheduled_in": "2025-03-03T23:00:00Z"}
+	]}`, &request)
+
+	from := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	flights, _, err := flightAware.Departures("KDEN", from, from.Add(6*time.Hour))
+	if err != nil {
+		t.Fatalf("Departures failed: %v", err)
+	}
+	if !strings.HasPrefix(request, "/airports/KDEN/flights/scheduled_departures?") {
+		t.Errorf("Unexpected request %s", request)
+	}
+	if len(flights) != 1 || !flights[0].Departing || flights[0].ScheduledArrival != "1:15 PM" || flights[0].Delay != 15 {
+		t.Errorf("Expected DL300 timed by its departure, got %+v", flights)
+	}
+}
+
+func TestFlightAwareArrivalDetails(t *testing.T) {
+	var request string
+	flightAware := newTestFlightAware(t, `{"flights": [{"ident": "UAL12", "ident_iata": "UA12", "status": "En Route",
+		"scheduled_in": "2025-03-03T18:00:00Z", "terminal_origin": "1", "gate_origin": "C7",
+		"terminal_destination": "B", "gate_destination": "B44", "baggage_claim": "9",
+		"origin": {"code": "KORD", "code_iata": "ORD", "city": "Chicago"}, "destination": {"code": "KDEN", "city": "Denver"},
+		"aircraft_type": "B39M", "registration": "N37502"}]}`, &request)
+
+	flight, err := flightAware.Lookup("UA12")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if flight.Terminal != "B" || flight.Gate != "B44" || flight.BaggageClaim != "9" {
+		t.Errorf("Expected the arrival terminal, gate and baggage claim, got %+v", flight)
+	}
+	if flight.Origin != "ORD" || flight.OriginCity != "Chicago" || flight.Destination != "KDEN" {
+		t.Errorf("Expected IATA codes where given and ICAO otherwise, got %+v", flight)
+	}
+	if flight.AircraftType != "B39M" || flight.Registration != "N37502" {
+		t.Errorf("Expected the aircraft, got %+v", flight)
+	}
+}
+
+func TestFlightAwareRegisterAndDeleteAlert(t *testing.T) {
+	var requests []string
+	var registered map[string]any
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(&registered)
+			w.Header().Set("Location", "/aeroapi/alerts/742001")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer api.Close()
+	flightAware := NewFlightAware("key", time.Second, time.UTC)
+	flightAware.baseURL = api.URL
+
+	alertID, err := flightAware.RegisterAlert("UA12", "https://shuttle.example.com/hooks/flightaware?token=s")
+	if err != nil {
+		t.Fatalf("RegisterAlert failed: %v", err)
+	}
+	if alertID != "742001" {
+		t.Errorf("Expected the alert ID from the Location header, got %q", alertID)
+	}
+	if registered["ident"] != "UAL12" || registered["target_url"] != "https://shuttle.example.com/hooks/flightaware?token=s" {
+		t.Errorf("Unexpected alert registration %v", registered)
+	}
+
+	if err := flightAware.DeleteAlert(alertID); err != nil {
+		t.Fatalf("DeleteAlert failed: %v", err)
+	}
+	if len(requests) != 2 || requests[1] != "DELETE /alerts/742001" {
+		t.Errorf("Unexpected requests %v", requests)
+	}
+}
+
+func TestFlightAwareRegisterAlertRejected(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"title": "Invalid argument"}`, http.StatusBadRequest)
+	}))
+	defer api.Close()
+	flightAware := NewFlightAware("key", time.Second, time.UTC)
+	flightAware.baseURL = api.URL
+
+	if _, err := flightAware.RegisterAlert("UA12", "https://shuttle.example.com/hooks/flightaware"); err == nil {
+		t.Error("Expected a refused alert to be an error")
+	}
+}
+
+func TestFlightAwareParseAlert(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	flightAware := NewFlightAware("key", time.Second, denver)
+
+	alert, err := flightAware.ParseAlert([]byte(`{"event_code": "cancelled", "alert_id": 9,
+		"flight": {"ident": "SWA1234", "status": "Scheduled", "scheduled_out": "2025-03-03T20:00:00Z", "scheduled_in": "2025-03-03T22:00:00Z"}}`))
+	if err != nil {
+		t.Fatalf("ParseAlert failed: %v", err)
+	}
+	if alert.AlertID != "9" || alert.Event != "cancelled" || alert.FlightNumber != "WN1234" {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+	arrival, _ := alert.Flight(false)
+	departure, _ := alert.Flight(true)
+	if arrival.Status != "Cancelled" || arrival.ExpectedArrival != "3:00 PM" || departure.ExpectedArrival != "1:00 PM" {
+		t.Errorf("Expected a cancelled flight timed either way, got %+v and %+v", arrival, departure)
+	}
+
+	for _, body := range []string{`not json`, `{"event_code": "arrival"}`, `{"flight": {"ident": "UAL12"}}`} {
+		if _, err := flightAware.ParseAlert([]byte(body)); err == nil {
+			t.Errorf("Expected %s to be rejected", body)
+		}
+	}
+}
+
+func TestFlightAwareOutageIsUnavailable(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer api.Close()
+	flightAware := NewFlightAware("key", time.Second, time.UTC)
+	flightAware.baseURL = api.URL
+
+	if _, err := flightAware.Lookup("AA100"); !IsUnavailable(err) {
+		t.Errorf("Expected an outage to count as unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// DefaultJSONFields maps board fields onto an aviationstack /v1/flights
+// response, used when no mapping is configured
+const DefaultJSONFields = "flight_number=data.0.flight.iata,airline=data.0.airline.name,status=data.0.flight_status," +
+	"scheduled=data.0.arrival.scheduled,estimated=data.0.arrival.estimated,actual=data.0.arrival.actual,delay=data.0.arrival.delay," +
+	"terminal=data.0.arrival.terminal,gate=data.0.arrival.gate,baggage=data.0.arrival.baggage," +
+	"origin=data.0.departure.iata,origin_city=data.0.departure.airport,destination=data.0.arrival.iata,destination_city=data.0.arrival.airport," +
+	"aircraft_type=data.0.aircraft.icao,registration=data.0.aircraft.registration"
+
+// jsonFieldNames are the board fields a mapping can fill in
+var jsonFieldNames = []string{
+	"flight_number", "airline", "status", "scheduled", "estimated", "actual", "delay",
+	"terminal", "gate", "baggage", "origin", "origin_city", "destination", "destination_city",
+	"aircraft_type", "registration",
+}
+
+// JSONAPI looks flights up with any HTTP API that answers with JSON, reading
+// each board field from the response by a configured path
+type JSONAPI struct {
+	name        string // Recorded as the source of the flights it supplies
+	urlTemplate string
+	apiKey      string
+	keyHeader   string
+	fields      map[string][]string // Board field to path in the response
+	instances   []string            // Path to the list of flight instances the fields read the first of, if any
+	client      *http.Client
+	location    *time.Location // Hotel time zone arrival times are shown in
+}
+
+// NewJSONAPI returns a client for the API at urlTemplate, where {flight},
+// {flight_icao} and {key} are replaced by the IATA and ICAO flight numbers
+// and the API key. The key is also sent in keyHeader if one is given.
+// fields maps board fields to dotted paths in the response, such as
+// "status=data.0.flight_status", and defaults to DefaultJSONFields.
+func NewJSONAPI(name, urlTemplate, apiKey, keyHeader, fields string, timeout time.Duration, location *time.Location) (*JSONAPI, error) {
+	if !strings.Contains(urlTemplate, "{flight}") && !strings.Contains(urlTemplate, "{flight_icao}") {
+		return nil, fmt.Errorf("URL %q has no {flight} or {flight_icao} placeholder", urlTemplate)
+	}
+	mapping, err := parseJSONFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONAPI{
+		name:        name,
+		urlTemplate: urlTemplate,
+		apiKey:      apiKey,
+		keyHeader:   keyHeader,
+		fields:      mapping,
+		instances:   instancesPath(mapping),
+		client:      &http.Client{Timeout: timeout},
+		location:    location,
+	}, nil
+}
+
+// parseJSONFields reads a comma-separated list of field=path pairs
+func parseJSONFields(fields string) (map[string][]string, error) {
+	if strings.TrimSpace(fields) == "" {
+		fields = DefaultJSONFields
+	}
+	known := map[string]bool{}
+	for _, name := range jsonFieldNames {
+		known[name] = true
+	}
+
+	mapping := map[string][]string{}
+	for _, pair := range strings.Split(fields, ",") {
+		name, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("Field mapping %q must be field=path", pair)
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown field %q, expected one of %s", name, strings.Join(jsonFieldNames, ", "))
+		}
+		mapping[name] = strings.Split(path, ".")
+	}
+	if mapping["scheduled"] == nil && mapping["estimated"] == nil && mapping["actual"] == nil {
+		return nil, fmt.Errorf("Field mapping needs at least one of scheduled, estimated or actual")
+	}
+	return mapping, nil
+}
+
+// instancesPath finds the list of flight instances when every mapped path
+// reads from its first element, like data.0 in the default mapping, so the
+// other instances can be read too. It returns nil if the paths don't share one.
+func instancesPath(mapping map[string][]string) []string {
+	var instances []string
+	for _, path := range mapping {
+		index := slices.Index(path, "0")
+		if index < 0 || (instances != nil && !slices.Equal(path[:index], instances)) {
+			return nil
+		}
+		instances = path[:index:index]
+	}
+	return instances
+}
+
+// Lookup fetches a flight from the API and reads its arrival
+func (j *JSONAPI) Lookup(flightNumber string) (flight board.Flight, err error) {
+	start := time.Now()
+	defer func() {
+		observe(j.name, flightNumber, start, err)
+	}()
+
+	data, err := j.fetch(flightNumber)
+	if err != nil {
+		return board.Flight{}, err
+	}
+	return j.toFlight(data, 0, flightNumber)
+}
+
+// LookupDay fetches a flight and reads the instance scheduled to arrive
+// between from and to. APIs like aviationstack list a flight's recent and
+// upcoming days together, and the first may not be the one asked for.
+func (j *JSONAPI) LookupDay(flightNumber string, from, to time.Time) (flight board.Flight, err error) {
+	start := time.Now()
+	defer func() {
+		observe(j.name, flightNumber, start, err)
+	}()
+
+	data, err := j.fetch(flightNumber)
+	if err != nil {
+		return board.Flight{}, err
+	}
+	if j.instances == nil {
+		return j.toFlight(data, 0, flightNumber)
+	}
+
+	instances, _ := jsonNode(data, j.instances).([]any)
+	for index := range instances {
+		flight, err := j.toFlight(data, index, flightNumber)
+		if err != nil {
+			continue
+		}
+		arrival, err := time.Parse(time.RFC3339, j.field(data, index, "scheduled"))
+		if err != nil {
+			arrival = flight.SortTime
+		}
+		if !arrival.Before(from) && arrival.Before(to) {
+			return flight, nil
+		}
+	}
+	return board.Flight{}, fmt.Errorf("Flight not found on %s", from.In(j.location).Format("Jan 2"))
+}
+
+// fetch calls the API for a flight and decodes its response
+func (j *JSONAPI) fetch(flightNumber string) (any, error) {
+	replacer := strings.NewReplacer(
+		"{flight}", url.QueryEscape(flightNumber),
+		"{flight_icao}", url.QueryEscape(board.ICAOFlightNumber(flightNumber)),
+		"{key}", url.QueryEscape(j.apiKey),
+	)
+	req, err := http.NewRequest("GET", replacer.Replace(j.urlTemplate), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request")
+	}
+	if j.keyHeader != "" {
+		req.Header.Set(j.keyHeader, j.apiKey)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, unavailable("Failed to connect to %s", j.name)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, unavailable("Failed to read %s response", j.name)
+	}
+	switch {
+	case statusUnavailable(resp.StatusCode):
+		return nil, unavailable("%s is unavailable: %s", j.name, resp.Status)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("Flight not found")
+	case resp.StatusCode >= 400:
+		return nil, fmt.Errorf("%s refused the lookup: %s", j.name, resp.Status)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s response: %s", j.name, err.Error())
+	}
+	return data, nil
+}
+
+// field reads a mapped field of one of the flight's instances in a response
+func (j *JSONAPI) field(data any, instance int, name string) string {
+	path, ok := j.fields[name]
+	if !ok {
+		return ""
+	}
+	if j.instances != nil {
+		path = slices.Clone(path)
+		path[len(j.instances)] = strconv.Itoa(instance)
+	}
+	return jsonValue(data, path)
+}
+
+// toFlight reads the mapped fields of one of the flight's instances out of a
+// response, timing it by its most accurate arrival time in the hotel's time
+// zone
+func (j *JSONAPI) toFlight(data any, instance int, flightNumber string) (board.Flight, error) {
+	field := func(name string) string {
+		return j.field(data, instance, name)
+	}
+
+	// Use most accurate time available (actual > estimated > scheduled)
+	var expected time.Time
+	for _, name := range []string{"actual", "estimated", "scheduled"} {
+		if value := field(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return board.Flight{}, fmt.Errorf("Failed to parse time: %s", err.Error())
+			}
+			expected = parsed
+			break
+		}
+	}
+	if expected.IsZero() {
+		return board.Flight{}, fmt.Errorf("Flight not found")
+	}
+	scheduled, err := time.Parse(time.RFC3339, field("scheduled"))
+	if err != nil {
+		scheduled = expected
+	}
+
+	// Prefer the API's own delay, falling back to how late the expected time is
+	delay, err := strconv.Atoi(field("delay"))
+	if err != nil {
+		delay = int(expected.Sub(scheduled).Minutes())
+	}
+
+	if number := field("flight_number"); number != "" {
+		flightNumber = number
+	}
+	if normalized, err := board.NormalizeFlightNumber(flightNumber); err == nil {
+		flightNumber = normalized
+	}
+
+	// Statuses like "landed" are shown capitalised like FlightAware's
+	status := field("status")
+	if status != "" {
+		status = strings.ToUpper(status[:1]) + status[1:]
+	}
+
+	return board.Flight{
+		FlightNumber:     flightNumber,
+		Airline:          field("airline"),
+		Status:           status,
+		ScheduledArrival: scheduled.In(j.location).Format("3:04 PM"),
+		ExpectedArrival:  expected.In(j.location).Format("3:04 PM"),
+		Delay:            delay,
+		IsDelayed:        delay > 0,
+		SortTime:         expected.In(j.location),
+		Terminal:         field("terminal"),
+		Gate:             field("gate"),
+		BaggageClaim:     field("baggage"),
+		Origin:           field("origin"),
+		OriginCity:       field("origin_city"),
+		Destination:      field("destination"),
+		DestinationCity:  field("destination_city"),
+		AircraftType:     field("aircraft_type"),
+		Registration:     field("registration"),
+		Source:           j.name,
+	}, nil
+}
+
+// jsonNode follows a path of object keys and array indexes through decoded
+// JSON and returns what's there, or nil if there's nothing there
+func jsonNode(data any, path []string) any {
+	for _, step := range path {
+		switch node := data.(type) {
+		case map[string]any:
+			data = node[step]
+		case []any:
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil
+			}
+			data = node[index]
+		default:
+			return nil
+		}
+	}
+	return data
+}
+
+// jsonValue returns the value at a path through decoded JSON as text, or ""
+// if there's nothing there
+func jsonValue(data any, path []string) string {
+	switch value := jsonNode(data, path).(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
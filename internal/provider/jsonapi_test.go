@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// aviationstackFlight is a trimmed aviationstack /v1/flights response
+const aviationstackFlight = `{"pagination": {"count": 1}, "data": [{
+	"flight_date": "2025-03-03", "flight_status": "active",
+	"departure": {"airport": "Dallas/Fort Worth International", "iata": "DFW"},
+	"arrival": {"airport": "Denver International", "iata": "DEN", "terminal": "B", "gate": "B12", "baggage": "7",
+		"delay": 25, "scheduled": "2025-03-03T19:00:00+00:00", "estimated": "2025-03-03T19:25:00+00:00", "actual": null},
+	"airline": {"name": "American Airlines", "iata": "AA"},
+	"flight": {"number": "100", "iata": "AA100", "icao": "AAL100"},
+	"aircraft": {"registration": "N123AA", "icao": "B738"}
+}]}`
+
+func TestJSONAPIDefaultFields(t *testing.T) {
+	var request string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r.URL.RequestURI()
+		w.Write([]byte(aviationstackFlight))
+	}))
+	defer api.Close()
+
+	denver, _ := time.LoadLocation("America/Denver")
+	jsonAPI, err := NewJSONAPI("aviationstack", api.URL+"/v1/flights?access_key={key}&flight_iata={flight}", "k&1", "", "", time.Second, denver)
+	if err != nil {
+		t.Fatalf("NewJSONAPI failed: %v", err)
+	}
+	flight, err := jsonAPI.Lookup("AA100")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if request != "/v1/flights?access_key=k%261&flight_iata=AA100" {
+		t.Errorf("Unexpected request %s", request)
+	}
+	if flight.FlightNumber != "AA100" || flight.Airline != "American Airlines" || flight.Status != "Active" {
+		t.Errorf("Unexpected flight %+v", flight)
+	}
+	if flight.ScheduledArrival != "12:00 PM" || flight.ExpectedArrival != "12:25 PM" || flight.Delay != 25 || !flight.IsDelayed {
+		t.Errorf("Expected arrival times in Denver with the API's delay, got %+v", flight)
+	}
+	if flight.Gate != "B12" || flight.Origin != "DFW" || flight.AircraftType != "B738" || flight.Source != "aviationstack" {
+		t.Errorf("Expected the mapped details and source, got %+v", flight)
+	}
+}
+
+func TestJSONAPILookupDayPicksTheDaysInstance(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"flight_status": "landed", "flight": {"iata": "AA100"}, "arrival": {"scheduled": "2025-03-02T19:00:00+00:00", "gate": "A1"}},
+			{"flight_status": "scheduled", "flight": {"iata": "AA100"}, "arrival": {"scheduled": "2025-03-03T19:00:00+00:00", "gate": "B12"}}
+		]}`))
+	}))
+	defer api.Close()
+
+	denver, _ := time.LoadLocation("America/Denver")
+	jsonAPI, err := NewJSONAPI("aviationstack", api.URL+"/v1/flights?flight_iata={flight}", "", "", "", time.Second, denver)
+	if err != nil {
+		t.Fatalf("NewJSONAPI failed: %v", err)
+	}
+	from := time.Date(2025, 3, 3, 3, 0, 0, 0, denver)
+	flight, err := jsonAPI.LookupDay("AA100", from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("LookupDay failed: %v", err)
+	}
+	if flight.Status != "Scheduled" || flight.Gate != "B12" || flight.ScheduledArrival != "12:00 PM" {
+		t.Errorf("Expected the instance arriving on Mar 3, got %+v", flight)
+	}
+
+	later := from.AddDate(0, 0, 5)
+	if _, err := jsonAPI.LookupDay("AA100", later, later.AddDate(0, 0, 1)); err == nil {
+		t.Error("Expected no flight on a day without an instance")
+	}
+}
+
+func TestJSONAPICustomFields(t *testing.T) {
+	var key string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("x-api-key")
+		w.Write([]byte(`{"flight": {"status": "landed", "times": {"sta": "2025-03-03T19:00:00Z", "eta": "2025-03-03T18:50:00Z"}}}`))
+	}))
+	defer api.Close()
+
+	jsonAPI, err := NewJSONAPI("backup", api.URL+"/flights/{flight_icao}", "secret", "x-api-key",
+		"status=flight.status, scheduled=flight.times.sta, estimated=flight.times.eta", time.Second, time.UTC)
+	if err != nil {
+		t.Fatalf("NewJSONAPI failed: %v", err)
+	}
+	flight, err := jsonAPI.Lookup("WN1234")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if key != "secret" || flight.FlightNumber != "WN1234" || flight.Status != "Landed" || flight.Delay != -10 || flight.IsDelayed {
+		t.Errorf("Unexpected flight %+v with key %q", flight, key)
+	}
+}
+
+func TestJSONAPIErrors(t *testing.T) {
+	status := http.StatusTooManyRequests
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer api.Close()
+
+	jsonAPI, _ := NewJSONAPI("backup", api.URL+"/{flight}", "", "", "", time.Second, time.UTC)
+	if _, err := jsonAPI.Lookup("AA100"); !IsUnavailable(err) {
+		t.Errorf("Expected a spent quota to count as unavailable, got %v", err)
+	}
+	status = http.StatusOK
+	if _, err := jsonAPI.Lookup("AA100"); err == nil || IsUnavailable(err) {
+		t.Errorf("Expected an empty result to be an unknown flight, got %v", err)
+	}
+
+	for _, fields := range []string{"status", "wingspan=data.0.wingspan", "status=data.0.flight_status"} {
+		if _, err := NewJSONAPI("backup", api.URL+"/{flight}", "", "", fields, time.Second, time.UTC); err == nil {
+			t.Errorf("Expected mapping %q to be rejected", fields)
+		}
+	}
+	if _, err := NewJSONAPI("backup", api.URL+"/flights", "", "", "", time.Second, time.UTC); err == nil {
+		t.Error("Expected a URL without a flight placeholder to be rejected")
+	}
+}
@@ -0,0 +1,134 @@
+// Package provider looks up live flight data for the board.
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/metrics"
+)
+
+// Provider resolves a flight number to its current schedule and status. The
+// returned flight carries only provider data; callers fill in the type, crew
+// and other board details.
+type Provider interface {
+	Lookup(flightNumber string) (board.Flight, error)
+}
+
+// DayProvider is a Provider that can look up the instance of a flight that
+// arrives between from and to, usually a service day, where Lookup gets
+// whichever instance the API puts first. Providers that only know one
+// instance of a flight don't implement it.
+type DayProvider interface {
+	LookupDay(flightNumber string, from, to time.Time) (board.Flight, error)
+}
+
+// LookupDay looks up the flight arriving between from and to with providers
+// that can tell a flight's days apart, and with Lookup otherwise
+func LookupDay(p Provider, flightNumber string, from, to time.Time) (board.Flight, error) {
+	if days, ok := p.(DayProvider); ok {
+		return days.LookupDay(flightNumber, from, to)
+	}
+	return p.Lookup(flightNumber)
+}
+
+// DepartureProvider looks up the instance of a flight that leaves airport
+// between from and to, timed by its departure. Providers that only know a
+// flight's arrival don't implement it.
+type DepartureProvider interface {
+	LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error)
+}
+
+// LookupDeparture looks up a departing flight with providers that can
+func LookupDeparture(p Provider, flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	if departures, ok := p.(DepartureProvider); ok {
+		return departures.LookupDeparture(flightNumber, airport, from, to)
+	}
+	return board.Flight{}, fmt.Errorf("The flight provider can't look up departures")
+}
+
+// AirportLister lists the flights due into and out of an airport between
+// from and to, soonest first. Departures carry departure times and have
+// Departing set. truncated is set when the provider stopped before the end
+// of the range, so the latest flights are missing. Providers that can't list
+// an airport's flights don't implement it.
+type AirportLister interface {
+	Arrivals(airport string, from, to time.Time) (flights []board.Flight, truncated bool, err error)
+	Departures(airport string, from, to time.Time) (flights []board.Flight, truncated bool, err error)
+}
+
+// Alerter registers push alerts so a flight's changes are sent to a webhook
+// instead of being polled for. Providers without alerts don't implement it.
+type Alerter interface {
+	// RegisterAlert asks for a flight's changes to be posted to targetURL and
+	// returns the new alert's ID
+	RegisterAlert(flightNumber, targetURL string) (string, error)
+	DeleteAlert(alertID string) error
+	// ParseAlert reads a pushed alert payload
+	ParseAlert(body []byte) (Alert, error)
+}
+
+// Alert is one change pushed for a flight with a registered alert
+type Alert struct {
+	AlertID      string // Registered alert it was sent for, if the payload says
+	Event        string // e.g. "departure", "arrival", "diverted" or "cancelled"
+	FlightNumber string
+
+	flight func(departing bool) (board.Flight, error)
+}
+
+// Flight returns the flight data the alert carries, timed by arrival, or by
+// departure for departing flights
+func (a Alert) Flight(departing bool) (board.Flight, error) {
+	return a.flight(departing)
+}
+
+// unavailableError is a failure of the provider itself, like a network
+// error, an outage or a spent quota, rather than of the flight asked about
+type unavailableError struct {
+	message string
+}
+
+func (e unavailableError) Error() string {
+	return e.message
+}
+
+// unavailable returns an error that IsUnavailable recognises
+func unavailable(format string, args ...any) error {
+	return unavailableError{message: fmt.Sprintf(format, args...)}
+}
+
+// IsUnavailable reports whether err means the provider itself couldn't be
+// used, so another one is worth trying
+func IsUnavailable(err error) bool {
+	return errors.As(err, &unavailableError{})
+}
+
+// statusUnavailable reports whether an API response status means the API
+// is down, out of quota, or won't take our key, which won't change until an
+// operator steps in
+func statusUnavailable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= 500
+}
+
+// observe logs and counts the outcome and latency of a flight data lookup
+func observe(provider, flightNumber string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	metrics.ProviderDuration.Observe(elapsed.Seconds(), provider)
+	duration := float64(elapsed.Microseconds()) / 1000
+	if err != nil {
+		metrics.ProviderRequests.Inc(provider, "error")
+		slog.Warn("provider call failed", "provider", provider, "flight", flightNumber, "duration_ms", duration, "error", err)
+		return
+	}
+	metrics.ProviderRequests.Inc(provider, "ok")
+	slog.Info("provider call", "provider", provider, "flight", flightNumber, "duration_ms", duration, "outcome", "ok")
+}
@@ -0,0 +1,169 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// Default and largest window the airport page lists, in hours
+const (
+	defaultAirportHours = 6
+	maxAirportHours     = 24
+)
+
+// airportWindow returns the range the airport page lists for hours from now.
+// The start is rounded down to the quarter hour so repeat visits share a
+// cached list.
+func (s *Server) airportWindow(hours int) (from, to time.Time) {
+	from = s.Clock.Now().Truncate(15 * time.Minute)
+	return from, from.Add(time.Duration(hours) * time.Hour)
+}
+
+// parseAirportHours reads the hours parameter, falling back to the default
+func parseAirportHours(value string) int {
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 1 {
+		return defaultAirportHours
+	}
+	return min(hours, maxAirportHours)
+}
+
+// airportFlights lists the home airport's arrivals and departures for the
+// next hours, marking the ones already on the board
+func (s *Server) airportFlights(isDemo bool, hours int) (listing AirportPageData, err error) {
+	lister := s.airportLister(isDemo)
+	if lister == nil || s.Config.Provider.HomeAirport == "" {
+		return AirportPageData{}, fmt.Errorf("The flight provider can't list airport arrivals and departures")
+	}
+
+	onBoard := map[string]bool{}
+	for _, flight := range s.Board.Sorted() {
+		onBoard[airportKey(flight)] = true
+	}
+	rows := func(flights []board.Flight) []AirportRow {
+		var rows []AirportRow
+		for _, flight := range flights {
+			rows = append(rows, AirportRow{Flight: flight, Key: airportKey(flight), OnBoard: onBoard[airportKey(flight)]})
+		}
+		return rows
+	}
+
+	from, to := s.airportWindow(hours)
+	arrivals, arrivalsTruncated, err := lister.Arrivals(s.Config.Provider.HomeAirport, from, to)
+	if err != nil {
+		return AirportPageData{}, fmt.Errorf("Could not load arrivals: %s", err.Error())
+	}
+	departures, departuresTruncated, err := lister.Departures(s.Config.Provider.HomeAirport, from, to)
+	if err != nil {
+		return AirportPageData{}, fmt.Errorf("Could not load departures: %s", err.Error())
+	}
+	return AirportPageData{
+		Arrivals:            rows(arrivals),
+		Departures:          rows(departures),
+		ArrivalsTruncated:   arrivalsTruncated,
+		DeparturesTruncated: departuresTruncated,
+	}, nil
+}
+
+// airportKey tells an arrival and a departure with the same flight number apart
+func airportKey(flight board.Flight) string {
+	if flight.Departing {
+		return "departure:" + flight.FlightNumber
+	}
+	return "arrival:" + flight.FlightNumber
+}
+
+// airportHandler lists the home airport's arrivals and departures so the
+// desk can tick the ones carrying crews
+func (s *Server) airportHandler(w http.ResponseWriter, r *http.Request) {
+	s.renderAirport(w, r, parseAirportHours(r.FormValue("hours")), "")
+}
+
+// renderAirport renders the airport page with an optional error message
+func (s *Server) renderAirport(w http.ResponseWriter, r *http.Request, hours int, errMsg string) {
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+
+	data, err := s.airportFlights(isDemo, hours)
+	if err != nil {
+		slog.Warn("could not list airport flights", "airport", s.Config.Provider.HomeAirport, "error", err)
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+	}
+	data.Airport = s.Config.Provider.HomeAirport
+	data.Hours = hours
+	data.Error = errMsg
+	s.renderTemplate(w, r, "airport", data)
+}
+
+// trackAirportHandler adds the ticked arrivals as pickups and departures as
+// dropoffs, with the crew count given for each
+func (s *Server) trackAirportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/airport", http.StatusSeeOther)
+		return
+	}
+
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+	hours := parseAirportHours(r.FormValue("hours"))
+
+	r.ParseForm()
+	selected := r.Form["track"]
+	if len(selected) == 0 {
+		s.renderAirport(w, r, hours, "Tick the flights carrying crews first")
+		return
+	}
+
+	// Take the flight data from the listing rather than trusting the form
+	listing, err := s.airportFlights(isDemo, hours)
+	if err != nil {
+		s.renderAirport(w, r, hours, err.Error())
+		return
+	}
+	listed := map[string]AirportRow{}
+	for _, row := range append(listing.Arrivals, listing.Departures...) {
+		listed[row.Key] = row
+	}
+
+	var problems []string
+	for _, key := range selected {
+		row, ok := listed[key]
+		if !ok {
+			_, flightNumber, _ := strings.Cut(key, ":")
+			problems = append(problems, fmt.Sprintf("%s is no longer listed", flightNumber))
+			continue
+		}
+		if row.OnBoard {
+			continue
+		}
+		crewCount, err := strconv.Atoi(strings.TrimSpace(r.FormValue("crew_" + key)))
+		if err != nil || crewCount < 1 {
+			problems = append(problems, fmt.Sprintf("%s needs a crew count of at least 1", row.FlightNumber))
+			continue
+		}
+
+		flight := row.Flight
+		flight.Type = "pickup"
+		if flight.Departing {
+			flight.Type = "dropoff"
+		}
+		flight.CrewCount = crewCount
+		flight.IsDemo = isDemo
+		flight.Stage = board.StagePending
+		s.Board.Add(flight)
+	}
+
+	if len(problems) > 0 {
+		s.renderAirport(w, r, hours, strings.Join(problems, "; "))
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestParseAirportHours(t *testing.T) {
+	cases := map[string]int{"": 6, "3": 3, "0": 6, "-2": 6, "48": 24, "soon": 6}
+	for value, want := range cases {
+		if got := parseAirportHours(value); got != want {
+			t.Errorf("parseAirportHours(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+// departuresOnly lists the same departures for every airport and day, as if
+// there were more than the provider lists at once
+type departuresOnly []board.Flight
+
+func (d departuresOnly) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return nil, false, nil
+}
+
+func (d departuresOnly) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return d, true, nil
+}
+
+func TestAirportPageSaysWhenAListIsCutOff(t *testing.T) {
+	srv := newTestServer(t)
+	srv.airport = departuresOnly{{FlightNumber: "DL300", Status: "Scheduled", SortTime: testStart.Add(2 * time.Hour), Departing: true}}
+
+	w := httptest.NewRecorder()
+	srv.airportHandler(w, httptest.NewRequest("GET", "/airport", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "More departures are due than the flight provider lists at once") {
+		t.Errorf("Expected the departures to be marked as cut off, got %s", body)
+	}
+	if strings.Contains(body, "More arrivals are due") {
+		t.Error("Arrivals were listed in full and shouldn't be marked as cut off")
+	}
+}
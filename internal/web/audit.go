@@ -0,0 +1,39 @@
+package web
+
+import (
+	"log/slog"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// recordAudit logs a change to a flight and adds it to the audit log, which
+// is archived with the day's board at rollover
+func (s *Server) recordAudit(username string, flightID int, action string, changes []string, at time.Time) {
+	slog.Info("flight changed", "user", username, "flight_id", flightID, "action", action, "changes", changes)
+	if err := s.Audit.Record(username, flightID, action, changes, at); err != nil {
+		slog.Error("could not save audit log", "error", err)
+	}
+}
+
+// archiveAudit moves the changes made before the service day that started at
+// today into the archive, each under the service day it was made on
+func (s *Server) archiveAudit(today time.Time) error {
+	return s.Audit.ArchiveBefore(today, func(entries []board.AuditEntry) error {
+		byDate := map[string][]board.AuditEntry{}
+		var dates []string
+		for _, entry := range entries {
+			date := s.serviceDateAt(entry.Time)
+			if byDate[date] == nil {
+				dates = append(dates, date)
+			}
+			byDate[date] = append(byDate[date], entry)
+		}
+		for _, date := range dates {
+			if err := s.Archive.SaveAudit(date, byDate[date]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// calendarHandler shows the user's feed URL and lets them replace the token
+func (s *Server) calendarHandler(w http.ResponseWriter, r *http.Request) {
+	username := s.Auth.CurrentUsername(r)
+	token, err := s.Feeds.Token(username, r.Method == "POST")
+	if err != nil {
+		s.renderTemplate(w, r, "calendar", CalendarPageData{Error: err.Error()})
+		return
+	}
+	if r.Method == "POST" {
+		http.Redirect(w, r, "/calendar", http.StatusSeeOther)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	path := r.Host + "/calendar.ics?token=" + token
+
+	s.renderTemplate(w, r, "calendar", CalendarPageData{
+		FeedURL:      scheme + "://" + path,
+		SubscribeURL: "webcal://" + path,
+	})
+}
+
+// calendarFeedHandler serves the board as an iCalendar feed, authenticated by token
+func (s *Server) calendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.Auth.User(s.Feeds.Owner(r.URL.Query().Get("token"))); !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="shuttle.ics"`)
+	w.Write([]byte(buildCalendar(s.Board.Sorted(), s.currentServiceDate(), s.Clock.Now(), s.driveTime(), s.Config.Provider.PollInterval)))
+}
+
+// calendarTypeLabels prefix event titles so runs are easy to tell apart
+var calendarTypeLabels = map[string]string{
+	"pickup":  "Pickup",
+	"dropoff": "Dropoff",
+	"both":    "Pickup/dropoff",
+}
+
+// buildCalendar renders flights as VEVENTs running from the shuttle's leave-by
+// time to the flight's arrival. Clients are asked to refresh as often as the
+// board polls for new times.
+func buildCalendar(dayFlights []board.Flight, date string, now time.Time, drive, refreshEvery time.Duration) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Shuttle Coordinator//Flight Board//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:Shuttle runs")
+	refresh := max(int(refreshEvery.Minutes()), 1)
+	writeICSLine(&b, fmt.Sprintf("REFRESH-INTERVAL;VALUE=DURATION:PT%dM", refresh))
+	writeICSLine(&b, fmt.Sprintf("X-PUBLISHED-TTL:PT%dM", refresh))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, flight := range dayFlights {
+		if flight.SortTime.IsZero() {
+			continue
+		}
+
+		start := flight.LeaveBy(drive)
+		end := flight.SortTime
+
+		description := []string{
+			fmt.Sprintf("%s %s", flight.Airline, flight.FlightNumber),
+			fmt.Sprintf("Expected arrival: %s (scheduled %s)", flight.ExpectedArrival, flight.ScheduledArrival),
+			fmt.Sprintf("Type: %s", flight.Type),
+			fmt.Sprintf("Crew: %d", flight.CrewCount),
+			fmt.Sprintf("Stage: %s", flight.StageLabel()),
+		}
+		if flight.IsDelayed {
+			description = append(description, fmt.Sprintf("Delayed %d min", flight.Delay))
+		}
+		if flight.Note != "" {
+			description = append(description, "Note: "+flight.Note)
+		}
+
+		status := "CONFIRMED"
+		if flight.CurrentStage() == board.StageNoShow {
+			status = "CANCELLED"
+		}
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:flight-%d-%s@shuttle-coordinator", flight.ID, date))
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART:"+start.UTC().Format("20060102T150405Z"))
+		writeICSLine(&b, "DTEND:"+end.UTC().Format("20060102T150405Z"))
+		summary := fmt.Sprintf("%s %s (%d crew)", calendarTypeLabels[flight.Type], flight.FlightNumber, flight.CrewCount)
+		writeICSLine(&b, "SUMMARY:"+icsEscape(strings.TrimSpace(summary)))
+		writeICSLine(&b, "DESCRIPTION:"+icsEscape(strings.Join(description, "\n")))
+		writeICSLine(&b, "STATUS:"+status)
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// icsEscape escapes text property values per RFC 5545
+func icsEscape(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(s)
+}
+
+// writeICSLine writes a content line, folding it at 75 octets as RFC 5545 requires.
+// Continuation lines start with a space, which counts towards their 75.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Don't split a multi-byte UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
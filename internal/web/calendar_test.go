@@ -0,0 +1,45 @@
+package web
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestBuildCalendar(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	dayFlights := []board.Flight{
+		{ID: 4, FlightNumber: "AA100", Airline: "American Airlines", Type: "pickup", CrewCount: 3,
+			Note: "Door 3, by the; pillar", SortTime: time.Date(2025, 3, 3, 14, 30, 0, 0, denver)},
+		{ID: 5, FlightNumber: "DL200", Type: "dropoff", CrewCount: 2, Stage: board.StageNoShow,
+			SortTime: time.Date(2025, 3, 3, 16, 0, 0, 0, denver)},
+		{ID: 6, FlightNumber: "UA300", Type: "pickup", CrewCount: 1},
+	}
+
+	ics := buildCalendar(dayFlights, "2025-03-03", time.Now(), 20*time.Minute, 5*time.Minute)
+
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected flights without a time to be left out, got %d events", strings.Count(ics, "BEGIN:VEVENT"))
+	}
+	// 2:30 PM MST is 21:30 UTC; leave-by is 20 minutes earlier
+	if !strings.Contains(ics, "DTSTART:20250303T211000Z\r\nDTEND:20250303T213000Z") {
+		t.Error("Expected AA100 to run from its leave-by time to its arrival")
+	}
+	if !strings.Contains(ics, "UID:flight-4-2025-03-03@shuttle-coordinator") {
+		t.Error("Expected a stable UID so calendar apps update events in place")
+	}
+	if !strings.Contains(strings.ReplaceAll(ics, "\r\n ", ""), `Note: Door 3\, by the\; pillar`) {
+		t.Error("Expected commas and semicolons in notes to be escaped")
+	}
+	if !strings.Contains(ics, "STATUS:CANCELLED") {
+		t.Error("Expected no-show flights to be marked cancelled")
+	}
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+	}
+}
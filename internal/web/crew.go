@@ -0,0 +1,125 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// addCrewHandler adds a person to a flight's roster
+func (s *Server) addCrewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	member := board.CrewMember{
+		Name:     strings.TrimSpace(r.FormValue("name")),
+		Position: r.FormValue("position"),
+		Room:     strings.TrimSpace(r.FormValue("room")),
+		Phone:    strings.TrimSpace(r.FormValue("phone")),
+	}
+
+	if member.Name == "" {
+		s.renderHome(w, r, "Crew member name is required")
+		return
+	}
+	validPosition := false
+	for _, position := range board.CrewPositions {
+		if member.Position == position {
+			validPosition = true
+			break
+		}
+	}
+	if !validPosition {
+		s.renderHome(w, r, "Choose a crew position")
+		return
+	}
+
+	err := s.Board.Update(id, func(flight *board.Flight) error {
+		for _, existing := range flight.Crew {
+			if existing.ID >= member.ID {
+				member.ID = existing.ID + 1
+			}
+		}
+		if member.ID == 0 {
+			member.ID = 1
+		}
+		flight.Crew = append(flight.Crew, member)
+		board.SyncCrewCount(flight)
+		return nil
+	})
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	s.recordAudit(s.Auth.CurrentUsername(r), id, "crew", []string{fmt.Sprintf("added %s (%s)", member.Name, member.Position)}, s.Clock.Now())
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// removeCrewHandler takes a person off a flight's roster
+func (s *Server) removeCrewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	memberID, _ := strconv.Atoi(r.FormValue("member"))
+
+	var removed board.CrewMember
+	err := s.Board.Update(id, func(flight *board.Flight) error {
+		i := board.CrewMemberIndex(flight, memberID)
+		if i == -1 {
+			return fmt.Errorf("Crew member not found")
+		}
+		removed = flight.Crew[i]
+		flight.Crew = append(flight.Crew[:i], flight.Crew[i+1:]...)
+		board.SyncCrewCount(flight)
+		return nil
+	})
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	s.recordAudit(s.Auth.CurrentUsername(r), id, "crew", []string{fmt.Sprintf("removed %s", removed.Name)}, s.Clock.Now())
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// checkInCrewHandler toggles whether a crew member has been collected at the curb
+func (s *Server) checkInCrewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	memberID, _ := strconv.Atoi(r.FormValue("member"))
+
+	err := s.Board.Update(id, func(flight *board.Flight) error {
+		i := board.CrewMemberIndex(flight, memberID)
+		if i == -1 {
+			return fmt.Errorf("Crew member not found")
+		}
+		flight.Crew[i].CheckedIn = !flight.Crew[i].CheckedIn
+		if flight.Crew[i].CheckedIn {
+			flight.Crew[i].CheckedInAt = s.Clock.Now()
+		} else {
+			flight.Crew[i].CheckedInAt = time.Time{}
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
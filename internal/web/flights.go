@@ -0,0 +1,288 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// homeHandler displays all flights sorted by arrival time
+func (s *Server) homeHandler(w http.ResponseWriter, r *http.Request) {
+	s.renderHome(w, r, "")
+}
+
+// renderHome renders the flight board with an optional error message
+func (s *Server) renderHome(w http.ResponseWriter, r *http.Request, errMsg string) {
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+
+	// Finished flights move to the collapsed "done today" section
+	var active, done []board.Flight
+	for _, flight := range s.Board.Sorted() {
+		if flight.IsDone() {
+			done = append(done, flight)
+		} else {
+			active = append(active, flight)
+		}
+	}
+
+	// Demo boards reload as often as the simulator moves so flights play out live
+	refresh := s.Config.UI.RefreshInterval
+	if demoTick := s.Config.Provider.PollInterval / time.Duration(s.Config.Demo.Speed); isDemo && demoTick < refresh {
+		refresh = demoTick
+	}
+
+	s.renderTemplate(w, r, "index", PageData{
+		Flights:     active,
+		DoneFlights: done,
+		Error:       errMsg,
+		IsDemo:      isDemo,
+		IsDesk:      user != nil && user.Role == "desk",
+
+		RefreshMillis: refresh.Milliseconds(),
+		RefreshLabel:  intervalLabel(refresh),
+	})
+}
+
+// intervalLabel formats a refresh interval for display, e.g. "5 min" or "30 sec"
+func intervalLabel(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d sec", int(d.Seconds()))
+}
+
+// parseFlightForm reads and validates the flight fields shared by the add and edit forms
+func parseFlightForm(r *http.Request) (flightNumber, flightType string, crewCount int, err error) {
+	flightNumber, err = board.NormalizeFlightNumber(r.FormValue("flight_number"))
+	if err != nil {
+		return "", "", 0, err
+	}
+	isPickup := r.FormValue("is_pickup") == "on"
+	isDropoff := r.FormValue("is_dropoff") == "on"
+
+	crewCount, convErr := strconv.Atoi(strings.TrimSpace(r.FormValue("crew_count")))
+	if convErr != nil || crewCount < 1 {
+		return "", "", 0, fmt.Errorf("Crew count must be a whole number of at least 1")
+	}
+
+	if !isPickup && !isDropoff {
+		return "", "", 0, fmt.Errorf("Select pickup, dropoff, or both")
+	}
+
+	// Determine operation type
+	flightType = "pickup"
+	if isPickup && isDropoff {
+		flightType = "both"
+	} else if isDropoff {
+		flightType = "dropoff"
+	}
+
+	return flightNumber, flightType, crewCount, nil
+}
+
+// lookupFlight resolves a flight number to live data, or fake data for demo accounts
+func (s *Server) lookupFlight(isDemo bool, flightNumber, flightType string, crewCount int) (board.Flight, error) {
+	return s.resolveFlight(isDemo, flightNumber, false, flightType, crewCount)
+}
+
+// lookupDeparture is lookupFlight for a flight leaving the home airport
+func (s *Server) lookupDeparture(isDemo bool, flightNumber, flightType string, crewCount int) (board.Flight, error) {
+	return s.resolveFlight(isDemo, flightNumber, true, flightType, crewCount)
+}
+
+// resolveFlight looks up today's instance of a flight by its arrival, or by
+// its departure if departing, and fills in the board details
+func (s *Server) resolveFlight(isDemo bool, flightNumber string, departing bool, flightType string, crewCount int) (board.Flight, error) {
+	source := s.cached
+	if isDemo {
+		source = s.demo
+	}
+
+	flight, err := s.lookupToday(source, flightNumber, departing)
+	if err != nil {
+		return board.Flight{}, fmt.Errorf("%s (Note: Free API tier may not include all flights)", err.Error())
+	}
+	flight.Type = flightType
+	flight.CrewCount = crewCount
+	flight.IsDemo = isDemo
+	return flight, nil
+}
+
+// addFlightHandler processes new flight additions
+func (s *Server) addFlightHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+
+	flightNumber, flightType, crewCount, err := parseFlightForm(r)
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	// Demo users get fake data, real users get live API data
+	flight, err := s.lookupFlight(isDemo, flightNumber, flightType, crewCount)
+	if err != nil {
+		errMsg := err.Error()
+		if nearby := s.didYouMean(isDemo, flightNumber); len(nearby) > 0 {
+			errMsg += ". Did you mean " + strings.Join(nearby, ", ") + "?"
+		}
+		s.renderHome(w, r, errMsg)
+		return
+	}
+
+	flight.Stage = board.StagePending
+	s.Board.Add(flight)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// editFlightHandler changes the crew count, type or flight number of a tracked flight.
+// The provider is only queried again when the flight number changes.
+func (s *Server) editFlightHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	old, found := s.Board.Get(id)
+	if !found {
+		s.renderHome(w, r, board.ErrFlightNotFound.Error())
+		return
+	}
+
+	flightNumber, flightType, crewCount, err := parseFlightForm(r)
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	// Resolve outside the board lock since it may call the flight API. A
+	// departure stays a departure under its new number.
+	var resolved *board.Flight
+	if flightNumber != old.FlightNumber {
+		lookup := s.lookupFlight
+		if old.Departing {
+			lookup = s.lookupDeparture
+		}
+		flight, err := lookup(isDemo, flightNumber, flightType, crewCount)
+		if err != nil {
+			s.renderHome(w, r, err.Error())
+			return
+		}
+		resolved = &flight
+	}
+
+	username := s.Auth.CurrentUsername(r)
+	now := s.Clock.Now()
+
+	var changes []string
+	var staleAlert string
+	err = s.Board.Update(id, func(flight *board.Flight) error {
+		before := *flight
+		if resolved != nil {
+			board.ApplyFlightData(flight, *resolved)
+			// The alert follows the old number; the poller registers a new one
+			staleAlert, flight.AlertID = flight.AlertID, ""
+		}
+		// A roster sets the count; saying otherwise means the roster needs editing
+		if len(flight.Crew) > 0 && crewCount != len(flight.Crew) {
+			return fmt.Errorf("Crew count follows the roster of %d; add or remove crew members to change it", len(flight.Crew))
+		}
+		flight.Type = flightType
+		flight.CrewCount = crewCount
+		board.SyncCrewCount(flight)
+
+		changes = board.DiffFlights(before, *flight)
+		if len(changes) > 0 {
+			flight.LastEditedBy = username
+			flight.LastEditedAt = now
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+
+	if len(changes) > 0 {
+		s.recordAudit(username, id, "edit", changes, now)
+	}
+	s.deleteAlert(old.FlightNumber, staleAlert)
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// stageHandler moves a flight to the next operational stage
+func (s *Server) stageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	username := s.Auth.CurrentUsername(r)
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	stage := r.FormValue("stage")
+
+	now := s.Clock.Now()
+	var from string
+	err := s.Board.Update(id, func(flight *board.Flight) error {
+		from = flight.CurrentStage()
+		return board.AdvanceStage(flight, stage, username, now)
+	})
+	if err != nil {
+		s.renderHome(w, r, err.Error())
+		return
+	}
+	s.recordAudit(username, id, "stage", []string{fmt.Sprintf("stage %s -> %s", from, stage)}, now)
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// removeFlightHandler deletes a flight from the tracking list
+func (s *Server) removeFlightHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+
+	if flight, found := s.Board.Get(id); found {
+		s.deleteAlert(flight.FlightNumber, flight.AlertID)
+	}
+	s.Board.Remove(id)
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// updateNoteHandler saves notes for individual flights
+func (s *Server) updateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	note := r.FormValue("note")
+
+	s.Board.Update(id, func(flight *board.Flight) error {
+		flight.Note = note
+		return nil
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"net/http"
+	"os"
+)
+
+// healthzHandler reports that the process is up. It never touches storage so a
+// slow disk can't get the process restarted.
+func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte("ok\n"))
+}
+
+// readyzHandler reports whether the server can take traffic: templates are
+// loaded, the data directory is writable and we aren't shutting down
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if reason := s.notReadyReason(r); reason != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(reason + "\n"))
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+// notReadyReason returns why the server isn't ready, or "" if it is
+func (s *Server) notReadyReason(r *http.Request) string {
+	if s.shuttingDown.Load() {
+		return "shutting down"
+	}
+	if s.templates == nil {
+		return "templates not loaded"
+	}
+	if err := s.checkStorage(); err != nil {
+		requestLogger(r).Warn("storage not reachable", "data_dir", s.Config.Server.DataDir, "error", err)
+		return "storage not reachable"
+	}
+	return ""
+}
+
+// checkStorage confirms the data directory exists and is writable
+func (s *Server) checkStorage() error {
+	if err := os.MkdirAll(s.Config.Server.DataDir, 0755); err != nil {
+		return err
+	}
+
+	probe, err := os.CreateTemp(s.Config.Server.DataDir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
@@ -0,0 +1,26 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyzWaitsForTemplates(t *testing.T) {
+	srv := newTestServer(t)
+	templates := srv.templates
+	srv.templates = nil
+
+	w := httptest.NewRecorder()
+	srv.readyzHandler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before templates load, got %d", w.Code)
+	}
+
+	srv.templates = templates
+	w = httptest.NewRecorder()
+	srv.readyzHandler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 once ready, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"net/http"
+	"os"
+	"sort"
+)
+
+// historyHandler lists archived service days and shows a selected day and
+// the changes made to it read-only
+func (s *Server) historyHandler(w http.ResponseWriter, r *http.Request) {
+	var data HistoryPageData
+
+	days, err := s.Archive.Days()
+	if err != nil {
+		data.Error = "Failed to read the archive: " + err.Error()
+	}
+	data.Days = days
+
+	if date := r.URL.Query().Get("date"); date != "" {
+		day, err := s.Archive.Load(date)
+		if os.IsNotExist(err) {
+			data.Error = "No archived board for " + date
+		} else if err != nil {
+			data.Error = err.Error()
+		} else {
+			sort.Slice(day.Flights, func(i, j int) bool {
+				return day.Flights[i].SortTime.Before(day.Flights[j].SortTime)
+			})
+			data.Flights = map[int]string{}
+			for _, flight := range day.Flights {
+				data.TotalCrew += flight.CrewCount
+				data.Flights[flight.ID] = flight.FlightNumber
+			}
+			data.Day = &day
+		}
+	}
+
+	s.renderTemplate(w, r, "history", data)
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/provider"
+)
+
+// alertFallback is how long the poller leaves a flight with a push alert
+// alone before looking it up anyway, in case an alert went missing
+const alertFallback = 30 * time.Minute
+
+// maxAlertBody caps the size of a pushed alert
+const maxAlertBody = 1 << 20
+
+// flightAwareHookHandler applies the AeroAPI alerts posted to
+// /hooks/flightaware. AeroAPI doesn't sign its callbacks, so the URL it was
+// registered with carries the webhook secret as a token.
+func (s *Server) flightAwareHookHandler(w http.ResponseWriter, r *http.Request) {
+	if s.alerter == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.Config.Provider.WebhookSecret)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAlertBody))
+	if err != nil {
+		http.Error(w, "Alert too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	alert, err := s.alerter.ParseAlert(body)
+	if err != nil {
+		slog.Warn("rejected flight alert", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Unmatched alerts are still acknowledged so AeroAPI doesn't retry them
+	updated := s.applyAlert(alert)
+	slog.Info("flight alert", "flight", alert.FlightNumber, "event", alert.Event, "alert_id", alert.AlertID, "updated", updated)
+	fmt.Fprintf(w, "Updated %d flights\n", updated)
+}
+
+// applyAlert updates the tracked flights an alert is for and returns how
+// many it updated: the ones it was registered for, or failing that live
+// flights with the same number, so recorded alerts can be replayed by hand
+func (s *Server) applyAlert(alert provider.Alert) int {
+	var matched []board.Flight
+	for _, flight := range s.Board.Sorted() {
+		if !flight.IsDemo && alert.AlertID != "" && flight.AlertID == alert.AlertID {
+			matched = append(matched, flight)
+		}
+	}
+	if len(matched) == 0 {
+		for _, flight := range s.Board.Sorted() {
+			if !flight.IsDemo && flight.FlightNumber == alert.FlightNumber {
+				matched = append(matched, flight)
+			}
+		}
+	}
+
+	updated := 0
+	for _, flight := range matched {
+		resolved, err := alert.Flight(flight.Departing)
+		if err != nil {
+			slog.Warn("could not apply flight alert", "flight", flight.FlightNumber, "error", err)
+			continue
+		}
+		s.applyRefresh(flight, resolved)
+		updated++
+	}
+	return updated
+}
+
+// alertTarget returns the URL alerts are posted to, with the webhook secret
+func (s *Server) alertTarget() string {
+	target, err := url.Parse(s.Config.Provider.WebhookURL)
+	if err != nil {
+		return s.Config.Provider.WebhookURL
+	}
+	query := target.Query()
+	query.Set("token", s.Config.Provider.WebhookSecret)
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// syncAlerts registers push alerts for the live flights that can still
+// change and drops them from flights that can't
+func (s *Server) syncAlerts() {
+	if s.alerter == nil {
+		return
+	}
+	for _, flight := range s.Board.Sorted() {
+		if flight.IsDemo {
+			continue
+		}
+		switch {
+		case flight.AlertID == "" && needsRefresh(flight):
+			alertID, err := s.alerter.RegisterAlert(flight.FlightNumber, s.alertTarget())
+			if err != nil {
+				slog.Warn("could not register flight alert", "flight", flight.FlightNumber, "error", err)
+				continue
+			}
+			registered := false
+			s.Board.Update(flight.ID, func(current *board.Flight) error {
+				// Skip if the flight was edited to a different number meanwhile
+				if current.FlightNumber == flight.FlightNumber && current.AlertID == "" {
+					current.AlertID = alertID
+					registered = true
+				}
+				return nil
+			})
+			if !registered {
+				s.deleteAlert(flight.FlightNumber, alertID)
+			}
+		case flight.AlertID != "" && !needsRefresh(flight):
+			s.deleteAlert(flight.FlightNumber, flight.AlertID)
+			s.Board.Update(flight.ID, func(current *board.Flight) error {
+				if current.AlertID == flight.AlertID {
+					current.AlertID = ""
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// deleteAlert stops a flight's push alert, logging rather than failing if
+// the provider can't be reached
+func (s *Server) deleteAlert(flightNumber, alertID string) {
+	if s.alerter == nil || alertID == "" {
+		return
+	}
+	if err := s.alerter.DeleteAlert(alertID); err != nil {
+		slog.Warn("could not delete flight alert", "flight", flightNumber, "alert_id", alertID, "error", err)
+	}
+}
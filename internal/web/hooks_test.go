@@ -0,0 +1,130 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/provider"
+)
+
+// stubAlerter reads alerts like AeroAPI and records registrations instead of
+// calling the API
+type stubAlerter struct {
+	*provider.FlightAware
+	registered []string // Flight number and target URL of each registration
+	deleted    []string
+}
+
+func (a *stubAlerter) RegisterAlert(flightNumber, targetURL string) (string, error) {
+	a.registered = append(a.registered, flightNumber+" "+targetURL)
+	return "alert-" + flightNumber, nil
+}
+
+func (a *stubAlerter) DeleteAlert(alertID string) error {
+	a.deleted = append(a.deleted, alertID)
+	return nil
+}
+
+// newAlertServer creates a test server with webhooks configured and alerts
+// going to a stub
+func newAlertServer(t *testing.T) (*Server, *stubAlerter) {
+	t.Helper()
+	srv := newTestServer(t)
+	srv.Config.Provider.WebhookURL = "https://shuttle.example.com/hooks/flightaware"
+	srv.Config.Provider.WebhookSecret = "s3cret"
+	alerter := &stubAlerter{FlightAware: provider.NewFlightAware("", time.Second, srv.Config.Location())}
+	srv.alerter = alerter
+	return srv, alerter
+}
+
+func TestFlightAwareHookAppliesRecordedAlert(t *testing.T) {
+	srv, _ := newAlertServer(t)
+	handler := srv.Handler()
+	flight := srv.Board.Add(board.Flight{FlightNumber: "UA12", Type: "pickup", CrewCount: 3, Status: "En Route", AlertID: "742001"})
+	recorded, err := os.ReadFile("testdata/flightaware_alert_diverted.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(method, target, body string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w.Code
+	}
+	if code := post("POST", "/hooks/flightaware?token=wrong", string(recorded)); code != http.StatusUnauthorized {
+		t.Errorf("Expected a wrong token to be refused, got %d", code)
+	}
+	if code := post("GET", "/hooks/flightaware?token=s3cret", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET to be refused, got %d", code)
+	}
+	if code := post("POST", "/hooks/flightaware?token=s3cret", `{"event_code": "arrival"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected an alert without a flight to be rejected, got %d", code)
+	}
+
+	if code := post("POST", "/hooks/flightaware?token=s3cret", string(recorded)); code != http.StatusOK {
+		t.Fatalf("Expected the recorded alert to be accepted, got %d", code)
+	}
+	updated, _ := srv.Board.Get(flight.ID)
+	if updated.Status != "Diverted" || updated.ExpectedArrival != "11:40 AM" || updated.Destination != "COS" {
+		t.Errorf("Expected the flight to show the diversion, got %+v", updated)
+	}
+	if !updated.RefreshedAt.Equal(srv.Clock.Now()) || updated.CrewCount != 3 {
+		t.Errorf("Expected provider data applied and board details kept, got %+v", updated)
+	}
+}
+
+func TestFlightAwareHookOffWithoutWebhook(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestServer(t).Handler().ServeHTTP(w, httptest.NewRequest("POST", "/hooks/flightaware", strings.NewReader("{}")))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the hook to be off without a webhook URL, got %d", w.Code)
+	}
+}
+
+func TestSyncAlertsRegistersAndDrops(t *testing.T) {
+	srv, alerter := newAlertServer(t)
+	live := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", Status: "En Route"})
+	landed := srv.Board.Add(board.Flight{FlightNumber: "DL300", Type: "pickup", Status: "Arrived / Gate Arrival", AlertID: "alert-DL300"})
+	srv.Board.Add(board.Flight{FlightNumber: "UA1", Type: "pickup", Status: "Scheduled", IsDemo: true})
+
+	srv.syncAlerts()
+
+	if len(alerter.registered) != 1 || alerter.registered[0] != "AA100 https://shuttle.example.com/hooks/flightaware?token=s3cret" {
+		t.Errorf("Expected only the live flight to be registered, got %v", alerter.registered)
+	}
+	if got, _ := srv.Board.Get(live.ID); got.AlertID != "alert-AA100" {
+		t.Errorf("Expected the alert ID to be kept on the flight, got %q", got.AlertID)
+	}
+	if got, _ := srv.Board.Get(landed.ID); got.AlertID != "" || len(alerter.deleted) != 1 {
+		t.Errorf("Expected the landed flight's alert to be dropped, got %q and deletions %v", got.AlertID, alerter.deleted)
+	}
+
+	srv.syncAlerts()
+	if len(alerter.registered) != 1 {
+		t.Errorf("Expected no second registration, got %v", alerter.registered)
+	}
+}
+
+func TestRefreshFlightsLeavesAlertedFlights(t *testing.T) {
+	srv, _ := newAlertServer(t)
+	fake := srv.Clock.(*clock.Fake)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", Status: "En Route", AlertID: "alert-AA100", RefreshedAt: srv.Clock.Now()})
+
+	srv.refreshFlights(context.Background())
+	if got, _ := srv.Board.Get(flight.ID); got.Status != "En Route" {
+		t.Errorf("Expected a flight with a fresh alert to be left alone, got %q", got.Status)
+	}
+
+	fake.Advance(alertFallback)
+	srv.refreshFlights(context.Background())
+	if got, _ := srv.Board.Get(flight.ID); got.Status != "Scheduled" {
+		t.Errorf("Expected the poller to fall back to a lookup, got %q", got.Status)
+	}
+}
@@ -0,0 +1,410 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// maxManifestSize caps uploaded rooming lists
+const maxManifestSize = 1 << 20
+
+// manifestColumns maps accepted header names to the field they hold
+var manifestColumns = map[string]string{
+	"flight":         "flight",
+	"flight #":       "flight",
+	"flight number":  "flight",
+	"flight_number":  "flight",
+	"date":           "date",
+	"crew":           "crew",
+	"crew count":     "crew",
+	"crew_count":     "crew",
+	"type":           "type",
+	"pickup":         "type",
+	"pickup/dropoff": "type",
+	"note":           "note",
+	"notes":          "note",
+}
+
+// manifestDateFormats are the date layouts accepted in a rooming list
+var manifestDateFormats = []string{"2006-01-02", "1/2/2006", "1/2/06", "01/02/2006"}
+
+// parseManifest reads a CSV or TSV rooming list. A header row naming the
+// columns is optional; without one the columns are flight, date, crew, type, notes.
+func parseManifest(data string, today string) ([]ImportRow, error) {
+	data = strings.TrimPrefix(data, "\ufeff")
+	firstLine, _, _ := strings.Cut(data, "\n")
+
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if strings.Contains(firstLine, "\t") {
+		reader.Comma = '\t'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read the file: %s", err.Error())
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("The file is empty")
+	}
+
+	columns := []string{"flight", "date", "crew", "type", "note"}
+	start := 0
+	if header := manifestHeader(records[0]); header != nil {
+		columns = header
+		start = 1
+	}
+
+	var rows []ImportRow
+	for i := start; i < len(records); i++ {
+		fields := map[string]string{}
+		blank := true
+		for col, value := range records[i] {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				blank = false
+			}
+			if col < len(columns) && columns[col] != "" {
+				fields[columns[col]] = value
+			}
+		}
+		if blank {
+			continue
+		}
+
+		row := ImportRow{
+			Line:         i + 1,
+			FlightNumber: fields["flight"],
+			Date:         fields["date"],
+			Crew:         fields["crew"],
+			Type:         fields["type"],
+			Note:         fields["note"],
+		}
+		validateImportRow(&row, today)
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("The file has no flights in it")
+	}
+	return rows, nil
+}
+
+// manifestHeader returns the column fields if the record is a header row, or nil.
+// A header must at least name the flight column, so data rows aren't mistaken for one.
+func manifestHeader(record []string) []string {
+	columns := make([]string, len(record))
+	hasFlight := false
+	for i, name := range record {
+		if field, ok := manifestColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[i] = field
+			hasFlight = hasFlight || field == "flight"
+		}
+	}
+	if !hasFlight {
+		return nil
+	}
+	return columns
+}
+
+// validateImportRow normalizes a row's fields and records the first problem found
+func validateImportRow(row *ImportRow, today string) {
+	row.Error = ""
+	if strings.TrimSpace(row.FlightNumber) == "" {
+		row.FlightNumber = ""
+		row.Error = "Missing flight number"
+		return
+	}
+	flightNumber, err := board.NormalizeFlightNumber(row.FlightNumber)
+	if err != nil {
+		row.Error = err.Error()
+		return
+	}
+	row.FlightNumber = flightNumber
+
+	if row.Date == "" {
+		row.Date = today
+	} else {
+		parsed := false
+		for _, layout := range manifestDateFormats {
+			if date, err := time.Parse(layout, row.Date); err == nil {
+				row.Date = date.Format("2006-01-02")
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			row.Error = fmt.Sprintf("Unrecognized date %q", row.Date)
+			return
+		}
+	}
+	if row.Date < today {
+		row.Error = "Date is in the past"
+		return
+	}
+	row.IsToday = row.Date == today
+
+	crew := 1
+	if row.Crew != "" {
+		count, err := strconv.Atoi(row.Crew)
+		if err != nil || count < 1 {
+			row.Error = fmt.Sprintf("Invalid crew count %q", row.Crew)
+			return
+		}
+		crew = count
+	}
+	row.CrewCount = crew
+
+	flightType, ok := parseManifestType(row.Type)
+	if !ok {
+		row.Error = fmt.Sprintf("Unrecognized type %q (use pickup, dropoff or both)", row.Type)
+		return
+	}
+	row.Type = flightType
+}
+
+// parseManifestType maps the spellings schedulers use onto pickup, dropoff or both
+func parseManifestType(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "pickup", "pick up", "p", "arrival", "arr":
+		return "pickup", true
+	case "dropoff", "drop off", "d", "departure", "dep":
+		return "dropoff", true
+	case "both", "b", "pickup/dropoff", "p/d":
+		return "both", true
+	}
+	return "", false
+}
+
+// markDuplicates flags rows already on the board, already scheduled, or repeated
+// earlier in the same file
+func (s *Server) markDuplicates(rows []ImportRow) {
+	onBoard := map[string]bool{}
+	for _, flight := range s.Board.Sorted() {
+		onBoard[flight.FlightNumber] = true
+	}
+	scheduled := s.Schedules.List()
+	seen := map[string]int{}
+
+	for i := range rows {
+		row := &rows[i]
+		row.Duplicate = ""
+		if row.Error != "" {
+			continue
+		}
+
+		key := row.FlightNumber + " " + row.Date
+		if line, ok := seen[key]; ok {
+			row.Duplicate = fmt.Sprintf("Same flight and date as line %d", line)
+		} else if row.IsToday && onBoard[row.FlightNumber] {
+			row.Duplicate = "Already on today's board"
+		} else {
+			for _, schedule := range scheduled {
+				if schedule.FlightNumber == row.FlightNumber && schedule.RunsOn(row.Date) {
+					row.Duplicate = "Already covered by a recurring schedule"
+					break
+				}
+			}
+		}
+		seen[key] = row.Line
+	}
+}
+
+// importHandler shows the upload form and previews an uploaded rooming list
+func (s *Server) importHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.renderTemplate(w, r, "import", ImportPageData{Status: s.currentImportStatus()})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxManifestSize)
+	data, err := readManifestUpload(r)
+	if err != nil {
+		s.renderTemplate(w, r, "import", ImportPageData{Status: s.currentImportStatus(), Error: err.Error()})
+		return
+	}
+
+	rows, err := parseManifest(data, s.currentServiceDate())
+	if err != nil {
+		s.renderTemplate(w, r, "import", ImportPageData{Status: s.currentImportStatus(), Error: err.Error()})
+		return
+	}
+	s.markDuplicates(rows)
+
+	s.renderTemplate(w, r, "import", ImportPageData{Rows: rows, Status: s.currentImportStatus()})
+}
+
+// readManifestUpload returns the uploaded file, or the pasted text if no file was sent
+func readManifestUpload(r *http.Request) (string, error) {
+	if err := r.ParseMultipartForm(maxManifestSize); err != nil && err != http.ErrNotMultipart {
+		return "", fmt.Errorf("Upload is too large or unreadable")
+	}
+
+	file, _, err := r.FormFile("manifest")
+	if err == nil {
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read the uploaded file")
+		}
+		if len(strings.TrimSpace(string(data))) > 0 {
+			return string(data), nil
+		}
+	}
+
+	if pasted := r.FormValue("pasted"); strings.TrimSpace(pasted) != "" {
+		return pasted, nil
+	}
+	return "", fmt.Errorf("Choose a file or paste the rooming list")
+}
+
+// importConfirmHandler adds the selected preview rows. Today's flights are looked
+// up in the background at a limited rate; later dates become one-off schedules.
+func (s *Server) importConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/import", http.StatusSeeOther)
+		return
+	}
+
+	today := s.currentServiceDate()
+
+	// The preview is echoed back in hidden fields, so validate every row again
+	r.ParseForm()
+	var rows []ImportRow
+	for _, value := range r.Form["include"] {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		index := strconv.Itoa(i)
+		row := ImportRow{
+			Line:         i,
+			FlightNumber: r.FormValue("flight_" + index),
+			Date:         r.FormValue("date_" + index),
+			Crew:         r.FormValue("crew_" + index),
+			Type:         r.FormValue("type_" + index),
+			Note:         r.FormValue("note_" + index),
+		}
+		validateImportRow(&row, today)
+		if row.Error == "" {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		s.renderTemplate(w, r, "import", ImportPageData{Status: s.currentImportStatus(), Error: "No valid rows were selected"})
+		return
+	}
+
+	status := &ImportStatus{StartedAt: s.Clock.Now(), StartedBy: s.Auth.CurrentUsername(r), Total: len(rows)}
+	s.importLock.Lock()
+	if s.lastImport != nil && !s.lastImport.Done {
+		s.importLock.Unlock()
+		s.renderTemplate(w, r, "import", ImportPageData{Status: s.currentImportStatus(), Error: "An import is already running"})
+		return
+	}
+	started := s.goBackground(func(ctx context.Context) {
+		s.runImport(ctx, status, rows)
+	})
+	if started {
+		s.lastImport = status
+	}
+	s.importLock.Unlock()
+
+	if !started {
+		http.Error(w, "The server is shutting down, try the import again shortly", http.StatusServiceUnavailable)
+		return
+	}
+	http.Redirect(w, r, "/import", http.StatusSeeOther)
+}
+
+// runImport adds imported rows, spacing provider lookups by the configured
+// lookup interval. If ctx is cancelled the rows not yet added are reported
+// as failed.
+func (s *Server) runImport(ctx context.Context, status *ImportStatus, rows []ImportRow) {
+	var lastLookup time.Time
+
+	for i, row := range rows {
+		var err error
+		if row.IsToday {
+			wait := s.Config.Provider.LookupInterval - s.Clock.Now().Sub(lastLookup)
+			if wait < 0 {
+				wait = 0
+			}
+			select {
+			case <-ctx.Done():
+				s.stopImport(status, rows[i:])
+				return
+			case <-s.Clock.After(wait):
+			}
+			lastLookup = s.Clock.Now()
+
+			var flight board.Flight
+			flight, err = s.lookupFlight(false, row.FlightNumber, row.Type, row.CrewCount)
+			if err == nil {
+				flight.Stage = board.StagePending
+				flight.Note = row.Note
+				s.Board.Add(flight)
+			}
+		} else {
+			// Future days are added by the schedule runner on the day itself
+			date, _ := time.Parse("2006-01-02", row.Date)
+			err = s.Schedules.Add(board.RecurringSchedule{
+				FlightNumber: row.FlightNumber,
+				Weekdays:     []time.Weekday{date.Weekday()},
+				StartDate:    row.Date,
+				EndDate:      row.Date,
+				Type:         row.Type,
+				CrewCount:    row.CrewCount,
+				Note:         row.Note,
+				CreatedBy:    status.StartedBy,
+			})
+		}
+
+		s.importLock.Lock()
+		if err != nil {
+			status.Failed = append(status.Failed, fmt.Sprintf("%s (%s): %s", row.FlightNumber, row.Date, err.Error()))
+		} else if row.IsToday {
+			status.Added++
+		} else {
+			status.Scheduled++
+		}
+		s.importLock.Unlock()
+	}
+
+	s.importLock.Lock()
+	status.Done = true
+	s.importLock.Unlock()
+}
+
+// stopImport ends an import cut short by shutdown, listing the rows it
+// didn't get to
+func (s *Server) stopImport(status *ImportStatus, remaining []ImportRow) {
+	s.importLock.Lock()
+	defer s.importLock.Unlock()
+	for _, row := range remaining {
+		status.Failed = append(status.Failed, fmt.Sprintf("%s (%s): not imported, the server shut down", row.FlightNumber, row.Date))
+	}
+	status.Done = true
+}
+
+// currentImportStatus returns a copy of the most recent import's progress
+func (s *Server) currentImportStatus() *ImportStatus {
+	s.importLock.Lock()
+	defer s.importLock.Unlock()
+
+	if s.lastImport == nil {
+		return nil
+	}
+	status := *s.lastImport
+	status.Failed = append([]string(nil), s.lastImport.Failed...)
+	return &status
+}
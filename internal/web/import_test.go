@@ -0,0 +1,186 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+func TestParseManifestWithHeader(t *testing.T) {
+	data := "Notes,Flight Number,Crew,Date,Type\n" +
+		"Captain first,aa 100,3,2025-03-05,both\n" +
+		",DL200,,3/6/2025,departure\n"
+
+	rows, err := parseManifest(data, "2025-03-05")
+	if err != nil {
+		t.Fatalf("parseManifest failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.FlightNumber != "AA100" || first.CrewCount != 3 || first.Type != "both" || first.Note != "Captain first" {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if !first.IsToday {
+		t.Error("Row dated today should go on today's board")
+	}
+
+	second := rows[1]
+	if second.Date != "2025-03-06" || second.Type != "dropoff" || second.CrewCount != 1 || second.IsToday {
+		t.Errorf("Unexpected second row: %+v", second)
+	}
+}
+
+func TestParseManifestTSVWithoutHeader(t *testing.T) {
+	data := "UA300\t\t2\tpickup\tRoom 12\n\nWN1\tnot a date\t1\t\t\n"
+
+	rows, err := parseManifest(data, "2025-03-05")
+	if err != nil {
+		t.Fatalf("parseManifest failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected blank lines to be skipped, got %d rows", len(rows))
+	}
+	if rows[0].Error != "" || rows[0].Date != "2025-03-05" || rows[0].Note != "Room 12" {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Error == "" {
+		t.Error("Expected an invalid date to be reported")
+	}
+}
+
+func TestParseManifestRejectsPastDatesAndBadTypes(t *testing.T) {
+	rows, err := parseManifest("AA100,2025-03-01,2,pickup\nAA101,2025-03-05,2,shuttle\n", "2025-03-05")
+	if err != nil {
+		t.Fatalf("parseManifest failed: %v", err)
+	}
+	if rows[0].Error != "Date is in the past" {
+		t.Errorf("Expected a past date error, got '%s'", rows[0].Error)
+	}
+	if rows[1].Error == "" {
+		t.Error("Expected an unknown type to be reported")
+	}
+}
+
+func TestMarkDuplicates(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100"})
+
+	rows, _ := parseManifest("AA100,2025-03-05,2\nDL200,2025-03-05,2\nDL200,2025-03-05,3\nAA100,2025-03-06,1\n", "2025-03-05")
+	srv.markDuplicates(rows)
+
+	if rows[0].Duplicate == "" {
+		t.Error("Flight already on today's board should be flagged")
+	}
+	if rows[1].Duplicate != "" {
+		t.Errorf("First DL200 should not be flagged, got '%s'", rows[1].Duplicate)
+	}
+	if rows[2].Duplicate == "" {
+		t.Error("Repeated DL200 should be flagged")
+	}
+	if rows[3].Duplicate != "" {
+		t.Errorf("AA100 on a later day should not be flagged, got '%s'", rows[3].Duplicate)
+	}
+}
+
+// waitForClock waits until code under test is blocked on the fake clock
+func waitForClock(t *testing.T, fake *clock.Fake) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); fake.Waiters() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Nothing started waiting on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunImportSpacesLookupsByClock(t *testing.T) {
+	srv := newTestServer(t)
+	fake := srv.Clock.(*clock.Fake)
+	rows := []ImportRow{
+		{FlightNumber: "AA100", Type: "pickup", CrewCount: 2, IsToday: true},
+		{FlightNumber: "DL300", Type: "pickup", CrewCount: 3, IsToday: true},
+		{FlightNumber: "UA12", Date: "2025-03-05", Type: "dropoff", CrewCount: 1},
+	}
+	status := &ImportStatus{Total: len(rows)}
+
+	done := make(chan struct{})
+	go func() {
+		srv.runImport(context.Background(), status, rows)
+		close(done)
+	}()
+
+	waitForClock(t, fake)
+	if flights := srv.Board.Sorted(); len(flights) != 1 {
+		t.Fatalf("Expected the second lookup to wait for the interval, got %d flights", len(flights))
+	}
+	fake.Advance(srv.Config.Provider.LookupInterval)
+	<-done
+
+	if status.Added != 2 || status.Scheduled != 1 || !status.Done {
+		t.Errorf("Unexpected import status %+v", status)
+	}
+}
+
+func TestRunImportStopsOnShutdown(t *testing.T) {
+	srv := newTestServer(t)
+	rows := []ImportRow{
+		{FlightNumber: "AA100", Type: "pickup", CrewCount: 2, IsToday: true},
+		{FlightNumber: "DL300", Type: "pickup", CrewCount: 3, IsToday: true},
+	}
+	status := &ImportStatus{Total: len(rows)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.runImport(ctx, status, rows)
+		close(done)
+	}()
+	waitForClock(t, srv.Clock.(*clock.Fake))
+	cancel()
+	<-done
+
+	if status.Added != 1 || len(status.Failed) != 1 || !status.Done {
+		t.Errorf("Expected the waiting row to be reported as not imported, got %+v", status)
+	}
+}
+
+func TestImportRefusedOnceShuttingDown(t *testing.T) {
+	srv := newTestServer(t)
+	srv.StartShutdown()
+
+	form := url.Values{"include": {"0"}, "flight_0": {"AA100"}, "date_0": {"2025-03-03"}, "crew_0": {"2"}, "type_0": {"pickup"}}
+	req := httptest.NewRequest("POST", "/import/confirm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	srv.importConfirmHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable || srv.currentImportStatus() != nil {
+		t.Errorf("Expected the import to be refused during shutdown, got %d and %+v", w.Code, srv.currentImportStatus())
+	}
+}
+
+func TestBackgroundJobsRefusedAfterRun(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	if srv.goBackground(func(context.Context) {}) {
+		t.Error("Expected no background job to start once Run has stopped")
+	}
+}
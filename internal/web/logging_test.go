@@ -0,0 +1,73 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestID(r)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "proxy-42")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if seen != "proxy-42" || w.Header().Get("X-Request-ID") != "proxy-42" {
+		t.Errorf("Expected an incoming request ID to be reused, got %q", seen)
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "bad id\nwith newline")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if seen == "" || seen == "bad id\nwith newline" {
+		t.Errorf("Expected an unsafe request ID to be replaced, got %q", seen)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(previous)
+
+	srv := newTestServer(t)
+	handler := withRequestID(srv.logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	})))
+
+	sessionID := srv.Auth.CreateSession("desk")
+	req := httptest.NewRequest("GET", "/reports", nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Access log is not JSON: %v (%s)", err, buf.String())
+	}
+	if entry["status"] != float64(http.StatusForbidden) || entry["path"] != "/reports" || entry["user"] != "desk" {
+		t.Errorf("Unexpected access log entry: %v", entry)
+	}
+	if entry["request_id"] == "" || entry["request_id"] == nil {
+		t.Error("Access log entry is missing the request ID")
+	}
+}
+
+func TestSetupLoggingRejectsUnknownFormat(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	if err := SetupLogging("info", "xml"); err == nil {
+		t.Error("Expected an unknown log format to be rejected")
+	}
+	if err := SetupLogging("loud", "text"); err == nil {
+		t.Error("Expected an unknown log level to be rejected")
+	}
+}
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"net/http"
+
+	"shuttletracker/internal/auth"
+	"shuttletracker/internal/metrics"
+)
+
+// loginHandler displays login form and processes login attempts
+func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" {
+		// Redirect if already logged in
+		cookie, err := r.Cookie(auth.SessionCookie)
+		if err == nil && s.Auth.Session(cookie.Value) != "" {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		s.renderTemplate(w, r, "login", nil)
+		return
+	}
+
+	if r.Method == "POST" {
+		username := r.FormValue("username")
+		password := r.FormValue("password")
+
+		if _, ok := s.Auth.Authenticate(username, password); !ok {
+			metrics.LoginFailures.Inc()
+			s.renderTemplate(w, r, "login", map[string]string{
+				"Error": "Invalid username or password",
+			})
+			return
+		}
+
+		// Create session and set secure cookie
+		sessionID := s.Auth.CreateSession(username)
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     auth.SessionCookie,
+			Value:    sessionID,
+			Path:     "/",
+			MaxAge:   int(auth.SessionTTL.Seconds()),
+			HttpOnly: true,                    // XSS protection
+			SameSite: http.SameSiteStrictMode, // CSRF protection
+			Secure:   s.Config.Server.TLSEnabled(),
+		})
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// logoutHandler destroys session and clears cookie
+func (s *Server) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(auth.SessionCookie)
+	if err == nil {
+		s.Auth.DeleteSession(cookie.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.Config.Server.TLSEnabled(),
+	})
+
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/metrics"
+)
+
+// instrumentRequests is middleware that counts and times requests by the mux
+// route they matched, so unknown paths don't create new series
+func instrumentRequests(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(status))
+		metrics.HTTPDuration.Observe(time.Since(start).Seconds(), route)
+	})
+}
+
+// writeMetrics writes every metric in the Prometheus text exposition format,
+// including gauges read from the board and sessions at scrape time
+func (s *Server) writeMetrics(w io.Writer) {
+	metrics.Write(w)
+
+	metrics.WriteGauge(w, "shuttle_active_sessions", "Logged-in sessions.", "",
+		map[string]float64{"": float64(s.Auth.ActiveSessions())})
+
+	byStatus := map[string]float64{}
+	byStage := map[string]float64{}
+	for _, flight := range s.Board.Sorted() {
+		status := strings.ToLower(flight.Status)
+		if status == "" {
+			status = "unknown"
+		}
+		byStatus[status]++
+		byStage[flight.CurrentStage()]++
+	}
+	metrics.WriteGauge(w, "shuttle_tracked_flights", "Flights on today's board by flight status.", "status", byStatus)
+	metrics.WriteGauge(w, "shuttle_tracked_flights_by_stage", "Flights on today's board by pickup stage.", "stage", byStage)
+}
+
+// metricsHandler serves /metrics, requiring the configured metrics token as a bearer token when it is set
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	token := s.Config.Server.MetricsToken
+	provided := []byte(r.Header.Get("Authorization"))
+	if token != "" && subtle.ConstantTimeCompare(provided, []byte("Bearer "+token)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.writeMetrics(w)
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shuttletracker/internal/metrics"
+)
+
+func TestInstrumentRequestsUsesRoutePattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/add", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusSeeOther)
+	})
+	handler := instrumentRequests(mux, mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/add", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/does-not-exist", nil))
+
+	var buf bytes.Buffer
+	metrics.HTTPRequests.Write(&buf)
+	out := buf.String()
+	if !strings.Contains(out, `shuttle_http_requests_total{route="/add",method="POST",status="303"}`) {
+		t.Errorf("Expected /add to be counted by route:\n%s", out)
+	}
+	if strings.Contains(out, "does-not-exist") {
+		t.Error("Unknown paths should not create their own series")
+	}
+}
@@ -0,0 +1,128 @@
+package web
+
+import (
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// PageData is the data passed to the HTML template
+type PageData struct {
+	Flights     []board.Flight // Flights still being worked
+	DoneFlights []board.Flight // Flights completed or marked no-show today
+	Error       string
+	IsDemo      bool // Whether the current user is a demo account
+	IsDesk      bool // Whether the current user is a desk account
+
+	RefreshMillis int64  // How often the board reloads itself
+	RefreshLabel  string // The reload interval for display, e.g. "5 min"
+}
+
+// FlightCard is the data passed to the flight-card partial
+type FlightCard struct {
+	board.Flight
+	IsDemo      bool // Whether the viewer is a demo account, which hides the crew roster
+	GateChanged bool // Whether the gate or terminal changed recently enough to point out
+}
+
+// HistoryPageData is the data passed to the history template
+type HistoryPageData struct {
+	Days      []string           // Archived service dates, newest first
+	Day       *board.ArchivedDay // Selected day, if any
+	TotalCrew int                // Crew moved on the selected day
+	Flights   map[int]string     // Flight numbers by ID, for the day's changes
+	Error     string
+}
+
+// ReportPageData is the data passed to the reports template
+type ReportPageData struct {
+	Report Report
+	Error  string
+}
+
+// SchedulesPageData is the data passed to the schedules template
+type SchedulesPageData struct {
+	Schedules []board.RecurringSchedule
+	Today     string
+	Error     string
+}
+
+// ImportRow is one flight parsed from an uploaded rooming list
+type ImportRow struct {
+	Line         int // Line number in the uploaded file
+	FlightNumber string
+	Date         string // Service date (YYYY-MM-DD once validated)
+	Crew         string // Crew count as written in the file
+	CrewCount    int
+	Type         string // "pickup", "dropoff", or "both" once validated
+	Note         string
+	IsToday      bool   // Whether the flight goes on today's board
+	Error        string // Validation problem; rows with errors can't be imported
+	Duplicate    string // Why the row looks like a duplicate, if it does
+}
+
+// ImportStatus tracks the progress of a background import
+type ImportStatus struct {
+	StartedAt time.Time
+	StartedBy string
+	Total     int
+	Added     int      // Flights added to today's board
+	Scheduled int      // Flights scheduled for later days
+	Failed    []string // Rows that could not be added, with the reason
+	Done      bool
+}
+
+// ImportPageData is the data passed to the import template
+type ImportPageData struct {
+	Rows   []ImportRow
+	Status *ImportStatus
+	Error  string
+}
+
+// AirportRow is one flight listed on the airport page
+type AirportRow struct {
+	board.Flight
+	Key     string // Identifies the row in the form, e.g. "arrival:AA100"
+	OnBoard bool   // Whether the flight is already being tracked
+}
+
+// AirportPageData is the data passed to the airport template
+type AirportPageData struct {
+	Airport    string
+	Hours      int // How far ahead the lists go
+	Arrivals   []AirportRow
+	Departures []AirportRow
+	Error      string
+
+	// Set when the provider stopped listing before the end of the window
+	ArrivalsTruncated   bool
+	DeparturesTruncated bool
+}
+
+// RunGroup is one shuttle run on the printed run sheet
+type RunGroup struct {
+	Label     string // e.g. "2 PM run"
+	Flights   []board.Flight
+	CrewCount int
+}
+
+// RunSheetData is the data passed to the print template
+type RunSheetData struct {
+	Date        string
+	GeneratedAt time.Time
+	Groups      []RunGroup
+	TotalCrew   int
+	DriveTime   int // Minutes allowed for the drive when computing leave-by times
+}
+
+// Drive returns the drive time allowed when computing leave-by times
+func (d RunSheetData) Drive() time.Duration {
+	return time.Duration(d.DriveTime) * time.Minute
+}
+
+// CalendarPageData is the data passed to the calendar template
+type CalendarPageData struct {
+	FeedURL      string // Feed URL for calendar apps that take a plain link
+	SubscribeURL string // webcal:// link that opens the subscribe dialog on phones
+	Error        string
+}
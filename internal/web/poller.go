@@ -0,0 +1,139 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/provider"
+)
+
+// runPoller keeps push alerts registered and refreshes the board from the
+// flight API every poll interval until ctx is cancelled
+func (s *Server) runPoller(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Provider.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.syncAlerts()
+			s.refreshFlights(ctx)
+		}
+	}
+}
+
+// runDemo plays demo flights forward in the simulator until ctx is cancelled.
+// It ticks faster than the poller by the simulator's speed so accelerated
+// flights still move along smoothly.
+func (s *Server) runDemo(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Provider.PollInterval / time.Duration(s.Config.Demo.Speed))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshDemoFlights()
+		}
+	}
+}
+
+// needsRefresh reports whether a flight's data can still change in a way
+// that matters to the shuttle. A departure stops mattering once it leaves.
+// Fallback APIs never say more than "Landed", where FlightAware goes on from
+// "Landed / Taxiing" to a gate arrival with the baggage claim.
+func needsRefresh(flight board.Flight) bool {
+	if flight.IsDone() || flight.CurrentStage() == board.StagePickedUp {
+		return false
+	}
+	status := strings.ToLower(flight.Status)
+	if flight.Departing && (strings.Contains(status, "en route") || strings.Contains(status, "departed")) {
+		return false
+	}
+	if status == "landed" {
+		return false
+	}
+	return !strings.Contains(status, "arrived") && !strings.Contains(status, "cancelled") && !strings.Contains(status, "diverted")
+}
+
+// refreshFlights looks up every live flight again, spacing calls like the
+// importer does to stay under the API rate limit. Departing flights are
+// looked up by their departure from the home airport. Flights with a push
+// alert are only looked up if nothing has arrived for them in a while. It
+// stops early if ctx is cancelled.
+func (s *Server) refreshFlights(ctx context.Context) {
+	first := true
+	for _, flight := range s.Board.Sorted() {
+		if flight.IsDemo || !needsRefresh(flight) {
+			continue
+		}
+		if flight.AlertID != "" && s.Clock.Now().Sub(flight.RefreshedAt) < alertFallback {
+			continue
+		}
+		if !first {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.Config.Provider.LookupInterval):
+			}
+		}
+		first = false
+
+		resolved, err := s.lookupToday(s.live, flight.FlightNumber, flight.Departing)
+		if err != nil {
+			slog.Warn("could not refresh flight", "flight", flight.FlightNumber, "error", err)
+			continue
+		}
+
+		s.applyRefresh(flight, resolved)
+	}
+}
+
+// lookupToday looks up the current service day's instance of a flight in
+// source, timed by its departure from the home airport if departing and by
+// its arrival otherwise
+func (s *Server) lookupToday(source provider.Provider, flightNumber string, departing bool) (board.Flight, error) {
+	from, to := s.serviceDayBounds(s.currentServiceDate())
+	if departing {
+		return provider.LookupDeparture(source, flightNumber, s.Config.Provider.HomeAirport, from, to)
+	}
+	return provider.LookupDay(source, flightNumber, from, to)
+}
+
+// refreshDemoFlights asks the simulator where each demo flight has got to.
+// There's no API behind it, so there's no need to space the lookups out.
+func (s *Server) refreshDemoFlights() {
+	for _, flight := range s.Board.Sorted() {
+		if !flight.IsDemo || !needsRefresh(flight) {
+			continue
+		}
+		resolved, err := s.lookupToday(s.demo, flight.FlightNumber, flight.Departing)
+		if err != nil {
+			slog.Warn("could not refresh demo flight", "flight", flight.FlightNumber, "error", err)
+			continue
+		}
+		s.applyRefresh(flight, resolved)
+	}
+}
+
+// applyRefresh copies fresh flight data onto the board, noting any gate change
+func (s *Server) applyRefresh(flight, resolved board.Flight) {
+	now := s.Clock.Now()
+	s.Board.Update(flight.ID, func(current *board.Flight) error {
+		// Skip if the flight was edited to a different number while we were looking it up
+		if current.FlightNumber != flight.FlightNumber {
+			return nil
+		}
+		before := *current
+		board.ApplyFlightData(current, resolved)
+		board.RecordGateChange(before, current, now)
+		current.RefreshedAt = now
+		return nil
+	})
+}
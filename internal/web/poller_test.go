@@ -0,0 +1,118 @@
+package web
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+func TestNeedsRefresh(t *testing.T) {
+	cases := []struct {
+		flight board.Flight
+		want   bool
+	}{
+		{board.Flight{Status: "En Route / On Time"}, true},
+		{board.Flight{Status: "Scheduled", Stage: board.StageDispatched}, true},
+		{board.Flight{Status: "Arrived / Gate Arrival"}, false},
+		{board.Flight{Status: "Landed / Taxiing"}, true},
+		{board.Flight{Status: "Landed", Source: "aviationstack"}, false},
+		{board.Flight{Status: "Cancelled"}, false},
+		{board.Flight{Status: "Diverted"}, false},
+		{board.Flight{Status: "scheduled", IsDemo: true}, true},
+		{board.Flight{Status: "Landed / Taxiing", Stage: board.StagePickedUp}, false},
+		{board.Flight{Status: "scheduled", Stage: board.StageCompleted}, false},
+		{board.Flight{Status: "Delayed", Departing: true}, true},
+		{board.Flight{Status: "En Route / On Time", Departing: true}, false},
+	}
+
+	for _, tc := range cases {
+		if got := needsRefresh(tc.flight); got != tc.want {
+			t.Errorf("needsRefresh(%+v) = %v, want %v", tc.flight, got, tc.want)
+		}
+	}
+}
+
+// departureLookups answers departure lookups with its flight and records what
+// was asked for
+type departureLookups struct {
+	stubProvider
+	flight   board.Flight
+	airport  string
+	from, to time.Time
+}
+
+func (d *departureLookups) LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	d.airport, d.from, d.to = airport, from, to
+	return d.flight, nil
+}
+
+func TestRefreshFlightsLooksDeparturesUp(t *testing.T) {
+	srv := newTestServer(t)
+	departs := testStart.Add(2 * time.Hour)
+	lookups := &departureLookups{flight: board.Flight{FlightNumber: "DL300", Status: "Delayed", Delay: 20, IsDelayed: true, SortTime: departs.Add(20 * time.Minute), Departing: true}}
+	srv.live = lookups
+
+	flight := srv.Board.Add(board.Flight{FlightNumber: "DL300", Status: "Scheduled", SortTime: departs, Departing: true, Type: "dropoff"})
+	srv.refreshFlights(context.Background())
+
+	from, to := srv.serviceDayBounds(srv.currentServiceDate())
+	if lookups.airport != "KDEN" || !lookups.from.Equal(from) || !lookups.to.Equal(to) {
+		t.Errorf("Expected today's departure from KDEN to be looked up, got %s from %s to %s", lookups.airport, lookups.from, lookups.to)
+	}
+	refreshed, _ := srv.Board.Get(flight.ID)
+	if refreshed.Status != "Delayed" || refreshed.Delay != 20 || !refreshed.Departing || refreshed.Type != "dropoff" {
+		t.Errorf("Expected the departure's new times, got %+v", refreshed)
+	}
+}
+
+func TestRefreshDemoFlightsFollowsSimulator(t *testing.T) {
+	srv := newTestServer(t)
+	fake := srv.Clock.(*clock.Fake)
+
+	flight, err := srv.lookupFlight(true, "AA100", "pickup", 2)
+	if err != nil {
+		t.Fatalf("lookupFlight failed: %v", err)
+	}
+	flight = srv.Board.Add(flight)
+	if flight.Status != "Scheduled" {
+		t.Fatalf("Expected a new demo flight to be scheduled, got %q", flight.Status)
+	}
+
+	fake.Advance(6 * time.Hour)
+	srv.refreshDemoFlights()
+	refreshed, _ := srv.Board.Get(flight.ID)
+	if refreshed.Status == flight.Status {
+		t.Errorf("Expected the demo flight to move on from %q after six hours", flight.Status)
+	}
+}
+
+func TestApplyRefreshHighlightsGateChange(t *testing.T) {
+	srv := newTestServer(t)
+	fake := srv.Clock.(*clock.Fake)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 2, Terminal: "B", Gate: "B12"})
+
+	srv.applyRefresh(flight, board.Flight{FlightNumber: "AA100", Status: "En Route", Terminal: "B", Gate: "B30"})
+	refreshed, _ := srv.Board.Get(flight.ID)
+	if refreshed.Gate != "B30" || refreshed.PreviousGate != "Terminal B, Gate B12" {
+		t.Fatalf("Expected the move from B12 to B30 to be recorded, got %+v", refreshed)
+	}
+
+	card := srv.templateFuncs()["flightCard"].(func(board.Flight, bool) FlightCard)
+	if !card(refreshed, false).GateChanged {
+		t.Error("Expected a fresh gate change to be highlighted")
+	}
+	w := httptest.NewRecorder()
+	srv.renderTemplate(w, httptest.NewRequest("GET", "/", nil), "index", PageData{Flights: []board.Flight{refreshed}})
+	if !strings.Contains(w.Body.String(), "gate-changed") || !strings.Contains(w.Body.String(), "was Terminal B, Gate B12") {
+		t.Errorf("Expected the card to point out the new gate:\n%s", w.Body.String())
+	}
+	fake.Advance(gateChangeHighlight)
+	if card(refreshed, false).GateChanged {
+		t.Error("Expected the highlight to fade")
+	}
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// driveTime returns the configured hotel-to-airport drive time
+func (s *Server) driveTime() time.Duration {
+	return time.Duration(s.Config.Service.DriveMinutes) * time.Minute
+}
+
+// runSheetGroups groups flights into hourly shuttle runs by leave-by time
+func runSheetGroups(dayFlights []board.Flight, drive time.Duration) []RunGroup {
+	var groups []RunGroup
+	var unscheduled []board.Flight
+
+	for _, flight := range dayFlights {
+		leaveBy := flight.LeaveBy(drive)
+		if leaveBy.IsZero() {
+			unscheduled = append(unscheduled, flight)
+			continue
+		}
+
+		label := leaveBy.Format("3 PM") + " run"
+		if len(groups) == 0 || groups[len(groups)-1].Label != label {
+			groups = append(groups, RunGroup{Label: label})
+		}
+		group := &groups[len(groups)-1]
+		group.Flights = append(group.Flights, flight)
+		group.CrewCount += flight.CrewCount
+	}
+
+	if len(unscheduled) > 0 {
+		group := RunGroup{Label: "No arrival time", Flights: unscheduled}
+		for _, flight := range unscheduled {
+			group.CrewCount += flight.CrewCount
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// runSheetData builds the run sheet for today's board
+func (s *Server) runSheetData() RunSheetData {
+	localTime := s.Config.Location()
+	dayFlights := s.Board.Sorted()
+	data := RunSheetData{
+		Date:        s.currentServiceDate(),
+		GeneratedAt: s.Clock.Now().In(localTime),
+		Groups:      runSheetGroups(dayFlights, s.driveTime()),
+		DriveTime:   int(s.driveTime().Minutes()),
+	}
+	for _, flight := range dayFlights {
+		data.TotalCrew += flight.CrewCount
+	}
+	return data
+}
+
+// printHandler renders the day's board as a printable run sheet
+func (s *Server) printHandler(w http.ResponseWriter, r *http.Request) {
+	s.renderTemplate(w, r, "print", s.runSheetData())
+}
+
+// printPDFHandler renders the run sheet as a PDF for printing without a browser
+func (s *Server) printPDFHandler(w http.ResponseWriter, r *http.Request) {
+	data := s.runSheetData()
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="run-sheet-%s.pdf"`, data.Date))
+	w.Write(runSheetPDF(data))
+}
+
+// Column positions for the PDF run sheet, in points from the left edge
+var runSheetColumns = []struct {
+	title string
+	x     float64
+	width float64
+}{
+	{"Leave by", 40, 55},
+	{"Flight", 100, 60},
+	{"Airline", 165, 120},
+	{"Expected", 290, 60},
+	{"Type", 355, 55},
+	{"Crew", 415, 35},
+	{"Stage", 455, 115},
+}
+
+// runSheetPDF lays out the run sheet on letter-size pages
+func runSheetPDF(data RunSheetData) []byte {
+	doc := &pdfDocument{}
+	y := 0.0
+
+	newPage := func() {
+		doc.addPage()
+		y = pdfPageHeight - 50
+		doc.text(40, y, 18, true, "Shuttle Run Sheet - "+data.Date)
+		y -= 16
+		doc.text(40, y, 9, false, fmt.Sprintf("Printed %s  |  %d crew  |  leave-by allows %d min drive",
+			data.GeneratedAt.Format("Jan 2 3:04 PM"), data.TotalCrew, data.DriveTime))
+		y -= 24
+		for _, col := range runSheetColumns {
+			doc.text(col.x, y, 9, true, col.title)
+		}
+		y -= 4
+		doc.line(40, y, pdfPageWidth-40, y)
+		y -= 14
+	}
+	newPage()
+
+	if len(data.Groups) == 0 {
+		doc.text(40, y, 11, false, "No flights on the board.")
+	}
+
+	for _, group := range data.Groups {
+		if y < 80 {
+			newPage()
+		}
+		doc.text(40, y, 11, true, fmt.Sprintf("%s  (%d crew)", group.Label, group.CrewCount))
+		y -= 16
+
+		for _, flight := range group.Flights {
+			if y < 60 {
+				newPage()
+			}
+			leaveBy := ""
+			if at := flight.LeaveBy(data.Drive()); !at.IsZero() {
+				leaveBy = at.Format("3:04 PM")
+			}
+			values := []string{
+				leaveBy,
+				flight.FlightNumber,
+				flight.Airline,
+				flight.ExpectedArrival,
+				flight.Type,
+				strconv.Itoa(flight.CrewCount),
+				flight.StageLabel(),
+			}
+			for i, col := range runSheetColumns {
+				doc.text(col.x, y, 10, i == 1, pdfFit(values[i], col.width, 10))
+			}
+			y -= 13
+
+			if flight.Note != "" {
+				doc.text(100, y, 9, false, pdfFit("Note: "+flight.Note, pdfPageWidth-140, 9))
+				y -= 13
+			}
+			y -= 3
+		}
+		y -= 8
+	}
+
+	return doc.bytes()
+}
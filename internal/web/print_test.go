@@ -0,0 +1,64 @@
+package web
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestRunSheetGroupsByLeaveByHour(t *testing.T) {
+	drive := 20 * time.Minute
+	denver, _ := time.LoadLocation("America/Denver")
+	dayFlights := []board.Flight{
+		{FlightNumber: "AA100", CrewCount: 3, SortTime: time.Date(2025, 3, 3, 14, 10, 0, 0, denver)},
+		{FlightNumber: "DL200", CrewCount: 2, SortTime: time.Date(2025, 3, 3, 14, 50, 0, 0, denver)},
+		{FlightNumber: "UA300", CrewCount: 4, SortTime: time.Date(2025, 3, 3, 15, 30, 0, 0, denver)},
+		{FlightNumber: "WN400", CrewCount: 1},
+	}
+
+	groups := runSheetGroups(dayFlights, drive)
+
+	// AA100 leaves at 1:50 PM, so it is on the 1 PM run rather than with DL200
+	labels := []string{}
+	for _, group := range groups {
+		labels = append(labels, group.Label)
+	}
+	if strings.Join(labels, "|") != "1 PM run|2 PM run|3 PM run|No arrival time" {
+		t.Fatalf("Unexpected groups: %v", labels)
+	}
+	if groups[1].CrewCount != 2 || groups[1].Flights[0].FlightNumber != "DL200" {
+		t.Errorf("Unexpected 2 PM run: %+v", groups[1])
+	}
+	if got := dayFlights[0].LeaveBy(drive).Format("3:04 PM"); got != "1:50 PM" {
+		t.Errorf("Expected AA100 leave-by 1:50 PM, got %s", got)
+	}
+}
+
+func TestRunSheetPDF(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	var dayFlights []board.Flight
+	for i := 0; i < 80; i++ {
+		dayFlights = append(dayFlights, board.Flight{
+			FlightNumber: "AA100",
+			Airline:      "American Airlines",
+			CrewCount:    2,
+			Note:         "Use (north) door",
+			SortTime:     time.Date(2025, 3, 3, 6+i/8, 0, 0, 0, denver),
+		})
+	}
+
+	pdf := runSheetPDF(RunSheetData{Date: "2025-03-03", GeneratedAt: time.Now(), Groups: runSheetGroups(dayFlights, 20*time.Minute)})
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("Output is not a complete PDF")
+	}
+	if !bytes.Contains(pdf, []byte(`Use \(north\) door`)) {
+		t.Error("Parentheses in notes should be escaped")
+	}
+	if bytes.Contains(pdf, []byte("/Count 1 ")) {
+		t.Error("80 flights should not fit on a single page")
+	}
+}
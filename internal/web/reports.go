@@ -0,0 +1,329 @@
+package web
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// Report summarizes archived flights for staffing decisions
+type Report struct {
+	From          string
+	To            string
+	TotalFlights  int
+	TotalCrew     int
+	PickupCrew    int // Crew picked up at the airport (pickup and both)
+	DropoffCrew   int // Crew dropped off at the airport (dropoff and both)
+	NoShows       int
+	NoShowRate    float64 // Percentage of flights marked no-show
+	Days          []VolumeStat
+	Weeks         []VolumeStat
+	Airlines      []DelayStat
+	Hours         []DelayStat
+	BusiestWindow []VolumeStat // 30-minute windows with the most crew, by arrival or departure time
+}
+
+// VolumeStat counts flights and crew for a day, week or time window
+type VolumeStat struct {
+	Label       string
+	Flights     int
+	Crew        int
+	PickupCrew  int
+	DropoffCrew int
+	NoShows     int
+}
+
+// DelayStat is the average and worst delay for a group of flights
+type DelayStat struct {
+	Label        string
+	Flights      int
+	AverageDelay float64
+	MaxDelay     int
+}
+
+// delayTotals accumulates delay figures for one group while building a report
+type delayTotals struct {
+	flights  int
+	total    int
+	maxDelay int
+}
+
+func (d *delayTotals) add(delay int) {
+	d.flights++
+	d.total += delay
+	if delay > d.maxDelay {
+		d.maxDelay = delay
+	}
+}
+
+// buildReport computes the report for a set of archived days, leaving out
+// demo flights archived before they were kept out of the archive
+func buildReport(days []board.ArchivedDay, from, to string, localTime *time.Location) Report {
+	report := Report{From: from, To: to}
+	dayStats := map[string]*VolumeStat{}
+	weekStats := map[string]*VolumeStat{}
+	windowStats := map[string]*VolumeStat{}
+	airlineDelays := map[string]*delayTotals{}
+	hourDelays := map[int]*delayTotals{}
+
+	for _, day := range days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		year, week := date.ISOWeek()
+		weekLabel := fmt.Sprintf("%d-W%02d", year, week)
+
+		for _, flight := range day.Flights {
+			if flight.IsDemo {
+				continue
+			}
+			buckets := []*VolumeStat{
+				statFor(dayStats, day.Date),
+				statFor(weekStats, weekLabel),
+			}
+
+			arrival := flight.SortTime.In(localTime)
+			if !flight.SortTime.IsZero() {
+				windowStart := time.Date(0, 1, 1, arrival.Hour(), arrival.Minute()/30*30, 0, 0, time.UTC)
+				windowLabel := windowStart.Format("15:04") + "-" + windowStart.Add(30*time.Minute).Format("15:04")
+				buckets = append(buckets, statFor(windowStats, windowLabel))
+
+				if hourDelays[arrival.Hour()] == nil {
+					hourDelays[arrival.Hour()] = &delayTotals{}
+				}
+				hourDelays[arrival.Hour()].add(flight.Delay)
+			}
+
+			airline := flight.Airline
+			if airline == "" {
+				airline = "Unknown"
+			}
+			if airlineDelays[airline] == nil {
+				airlineDelays[airline] = &delayTotals{}
+			}
+			airlineDelays[airline].add(flight.Delay)
+
+			pickupCrew, dropoffCrew := 0, 0
+			if flight.Type == "pickup" || flight.Type == "both" {
+				pickupCrew = flight.CrewCount
+			}
+			if flight.Type == "dropoff" || flight.Type == "both" {
+				dropoffCrew = flight.CrewCount
+			}
+			noShow := 0
+			if flight.CurrentStage() == board.StageNoShow {
+				noShow = 1
+			}
+
+			for _, stat := range buckets {
+				stat.Flights++
+				stat.Crew += flight.CrewCount
+				stat.PickupCrew += pickupCrew
+				stat.DropoffCrew += dropoffCrew
+				stat.NoShows += noShow
+			}
+
+			report.TotalFlights++
+			report.TotalCrew += flight.CrewCount
+			report.PickupCrew += pickupCrew
+			report.DropoffCrew += dropoffCrew
+			report.NoShows += noShow
+		}
+	}
+
+	if report.TotalFlights > 0 {
+		report.NoShowRate = float64(report.NoShows) * 100 / float64(report.TotalFlights)
+	}
+
+	report.Days = sortedStats(dayStats)
+	report.Weeks = sortedStats(weekStats)
+
+	// Busiest windows first, ties broken by time of day
+	windows := sortedStats(windowStats)
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Crew > windows[j].Crew
+	})
+	if len(windows) > 5 {
+		windows = windows[:5]
+	}
+	report.BusiestWindow = windows
+
+	for airline, totals := range airlineDelays {
+		report.Airlines = append(report.Airlines, totals.stat(airline))
+	}
+	sort.Slice(report.Airlines, func(i, j int) bool {
+		return report.Airlines[i].Label < report.Airlines[j].Label
+	})
+
+	for hour := 0; hour < 24; hour++ {
+		if totals, ok := hourDelays[hour]; ok {
+			label := time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC).Format("3 PM")
+			report.Hours = append(report.Hours, totals.stat(label))
+		}
+	}
+
+	return report
+}
+
+// statFor returns the stat for a label, creating it on first use
+func statFor(stats map[string]*VolumeStat, label string) *VolumeStat {
+	if stats[label] == nil {
+		stats[label] = &VolumeStat{Label: label}
+	}
+	return stats[label]
+}
+
+// sortedStats flattens a stat map ordered by label
+func sortedStats(stats map[string]*VolumeStat) []VolumeStat {
+	var sorted []VolumeStat
+	for _, stat := range stats {
+		sorted = append(sorted, *stat)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Label < sorted[j].Label
+	})
+	return sorted
+}
+
+func (d *delayTotals) stat(label string) DelayStat {
+	return DelayStat{
+		Label:        label,
+		Flights:      d.flights,
+		AverageDelay: float64(d.total) / float64(d.flights),
+		MaxDelay:     d.maxDelay,
+	}
+}
+
+// reportRange reads the from/to query parameters, defaulting to the 30 days
+// up to today's service date
+func reportRange(r *http.Request, today string) (from, to string, err error) {
+	from = r.URL.Query().Get("from")
+	to = r.URL.Query().Get("to")
+	if from == "" {
+		day, _ := time.Parse("2006-01-02", today)
+		from = day.AddDate(0, 0, -30).Format("2006-01-02")
+	}
+	if to == "" {
+		to = today
+	}
+
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return "", "", fmt.Errorf("Invalid date: %s", date)
+		}
+	}
+	if from > to {
+		return "", "", fmt.Errorf("Start date must be before end date")
+	}
+	return from, to, nil
+}
+
+// reportsHandler shows crew volume and delay analytics from the archive
+func (s *Server) reportsHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := reportRange(r, s.currentServiceDate())
+	if err != nil {
+		s.renderTemplate(w, r, "reports", ReportPageData{Error: err.Error()})
+		return
+	}
+
+	days, err := s.Archive.Range(from, to)
+	if err != nil {
+		s.renderTemplate(w, r, "reports", ReportPageData{Error: "Failed to read the archive: " + err.Error()})
+		return
+	}
+
+	s.renderTemplate(w, r, "reports", ReportPageData{Report: buildReport(days, from, to, s.Config.Location())})
+}
+
+// reportsCSVHandler exports archived flights (kind=flights) or daily totals (kind=daily) as CSV
+func (s *Server) reportsCSVHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := reportRange(r, s.currentServiceDate())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	days, err := s.Archive.Range(from, to)
+	if err != nil {
+		http.Error(w, "Failed to read the archive", http.StatusInternalServerError)
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = "flights"
+	}
+	if kind != "flights" && kind != "daily" {
+		http.Error(w, "Unknown export kind", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="shuttle-%s-%s-to-%s.csv"`, kind, from, to))
+
+	out := csv.NewWriter(w)
+	if kind == "daily" {
+		writeDailyCSV(out, buildReport(days, from, to, s.Config.Location()))
+	} else {
+		writeFlightsCSV(out, days, s.Config.Location())
+	}
+	out.Flush()
+}
+
+// writeDailyCSV writes one row of totals per archived day
+func writeDailyCSV(out *csv.Writer, report Report) {
+	out.Write([]string{"date", "flights", "crew", "pickup_crew", "dropoff_crew", "no_shows"})
+	for _, day := range report.Days {
+		out.Write([]string{
+			day.Label,
+			strconv.Itoa(day.Flights),
+			strconv.Itoa(day.Crew),
+			strconv.Itoa(day.PickupCrew),
+			strconv.Itoa(day.DropoffCrew),
+			strconv.Itoa(day.NoShows),
+		})
+	}
+}
+
+// writeFlightsCSV writes one row per archived flight
+func writeFlightsCSV(out *csv.Writer, days []board.ArchivedDay, localTime *time.Location) {
+	out.Write([]string{"date", "flight", "airline", "type", "crew", "scheduled", "expected", "delay_minutes", "stage", "note"})
+	for _, day := range days {
+		for _, flight := range day.Flights {
+			if flight.IsDemo {
+				continue
+			}
+			expected := ""
+			if !flight.SortTime.IsZero() {
+				expected = flight.SortTime.In(localTime).Format("15:04")
+			}
+			out.Write([]string{
+				day.Date,
+				csvText(flight.FlightNumber),
+				csvText(flight.Airline),
+				csvText(flight.Type),
+				strconv.Itoa(flight.CrewCount),
+				flight.ScheduledArrival,
+				expected,
+				strconv.Itoa(flight.Delay),
+				flight.CurrentStage(),
+				csvText(flight.Note),
+			})
+		}
+	}
+}
+
+// csvText quotes text that a spreadsheet would otherwise run as a formula
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
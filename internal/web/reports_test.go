@@ -0,0 +1,90 @@
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestBuildReport(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	days := []board.ArchivedDay{
+		{Date: "2025-03-03", Flights: []board.Flight{
+			{FlightNumber: "AA100", Airline: "American", Type: "both", CrewCount: 4, Delay: 30, Stage: board.StageCompleted,
+				SortTime: time.Date(2025, 3, 3, 14, 40, 0, 0, denver)},
+			{FlightNumber: "DL200", Airline: "Delta", Type: "pickup", CrewCount: 2, Delay: 0, Stage: board.StageNoShow,
+				SortTime: time.Date(2025, 3, 3, 14, 50, 0, 0, denver)},
+			{FlightNumber: "UA300", Airline: "United", Type: "pickup", CrewCount: 5, Delay: 90, IsDemo: true,
+				SortTime: time.Date(2025, 3, 3, 14, 45, 0, 0, denver)},
+		}},
+		{Date: "2025-03-10", Flights: []board.Flight{
+			{FlightNumber: "AA100", Airline: "American", Type: "dropoff", CrewCount: 3, Delay: 10, Stage: board.StageCompleted,
+				SortTime: time.Date(2025, 3, 10, 9, 5, 0, 0, denver)},
+		}},
+	}
+
+	report := buildReport(days, "2025-03-01", "2025-03-31", denver)
+
+	if report.TotalFlights != 3 || report.TotalCrew != 9 {
+		t.Errorf("Expected 3 flights and 9 crew, got %d and %d", report.TotalFlights, report.TotalCrew)
+	}
+	if report.PickupCrew != 6 || report.DropoffCrew != 7 {
+		t.Errorf("Expected 6 pickup and 7 dropoff crew, got %d and %d", report.PickupCrew, report.DropoffCrew)
+	}
+	if report.NoShows != 1 {
+		t.Errorf("Expected 1 no-show, got %d", report.NoShows)
+	}
+	if len(report.Weeks) != 2 {
+		t.Errorf("Expected 2 weeks, got %d", len(report.Weeks))
+	}
+
+	if len(report.Airlines) != 2 || report.Airlines[0].Label != "American" {
+		t.Fatalf("Expected American and Delta delay stats, got %+v", report.Airlines)
+	}
+	if report.Airlines[0].AverageDelay != 20 || report.Airlines[0].MaxDelay != 30 {
+		t.Errorf("Expected American average 20 and max 30, got %+v", report.Airlines[0])
+	}
+
+	if len(report.BusiestWindow) == 0 || report.BusiestWindow[0].Label != "14:30-15:00" || report.BusiestWindow[0].Crew != 6 {
+		t.Errorf("Expected 14:30-15:00 with 6 crew to be busiest, got %+v", report.BusiestWindow)
+	}
+}
+
+func TestBuildReportEmpty(t *testing.T) {
+	report := buildReport(nil, "2025-03-01", "2025-03-31", time.UTC)
+	if report.TotalFlights != 0 || report.NoShowRate != 0 {
+		t.Errorf("Expected an empty report, got %+v", report)
+	}
+}
+
+func TestWriteFlightsCSVEscapesFormulas(t *testing.T) {
+	days := []board.ArchivedDay{{Date: "2025-03-03", Flights: []board.Flight{
+		{FlightNumber: "AA100", Airline: "American", Type: "pickup", CrewCount: 2, Note: "=HYPERLINK(\"http://x\")"},
+		{FlightNumber: "DL200", Airline: "Delta", Type: "pickup", CrewCount: 1, Note: "-2 crew, call desk"},
+		{FlightNumber: "WN400", Airline: "Southwest", Type: "pickup", CrewCount: 1, Note: "\t=1+1"},
+		{FlightNumber: "B6500", Airline: "JetBlue", Type: "pickup", CrewCount: 1, Note: "\r@SUM(A1)"},
+		{FlightNumber: "UA300", Airline: "United", Type: "pickup", CrewCount: 1, IsDemo: true},
+	}}}
+
+	var buf bytes.Buffer
+	out := csv.NewWriter(&buf)
+	writeFlightsCSV(out, days, time.UTC)
+	out.Flush()
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("Expected a header and four live flights, got %v", rows)
+	}
+	for _, row := range rows[1:] {
+		if note := row[len(row)-1]; !strings.HasPrefix(note, "'") {
+			t.Errorf("Expected note %q to be quoted so it can't run as a formula", note)
+		}
+	}
+}
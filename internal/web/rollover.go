@@ -0,0 +1,107 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// serviceDate returns the service day a moment belongs to. Times before the
+// rollover time count towards the previous day, so late-night arrivals stay on
+// the board they were added to.
+func serviceDate(t time.Time, localTime *time.Location, hour, minute int) string {
+	local := t.In(localTime)
+
+	if local.Hour()*60+local.Minute() < hour*60+minute {
+		local = local.AddDate(0, 0, -1)
+	}
+	return local.Format("2006-01-02")
+}
+
+// serviceDateAt returns the service date for a moment using the configured time zone and rollover time
+func (s *Server) serviceDateAt(t time.Time) string {
+	hour, minute := s.Config.Rollover()
+	return serviceDate(t, s.Config.Location(), hour, minute)
+}
+
+// currentServiceDate returns today's service date
+func (s *Server) currentServiceDate() string {
+	return s.serviceDateAt(s.Clock.Now())
+}
+
+// serviceDayBounds returns when a service day starts and when the next one does
+func (s *Server) serviceDayBounds(date string) (start, end time.Time) {
+	hour, minute := s.Config.Rollover()
+	day, _ := time.ParseInLocation("2006-01-02", date, s.Config.Location())
+	start = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, s.Config.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// runRollover archives the board whenever a new service day starts and fills
+// the fresh board from recurring schedules. It checks once a minute until ctx
+// is cancelled.
+func (s *Server) runRollover(ctx context.Context) {
+	currentDay := s.currentServiceDate()
+	s.populateBoard(currentDay)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			currentDay = s.checkRollover(currentDay)
+		}
+	}
+}
+
+// checkRollover rolls the board over if the clock has moved past the service
+// day it holds, and returns the day the board holds afterwards. Failed
+// archiving is retried on the next check, and failed schedule lookups every
+// 15 minutes.
+func (s *Server) checkRollover(currentDay string) string {
+	now := s.Clock.Now()
+	today := s.serviceDateAt(now)
+	if today == currentDay {
+		if now.Minute()%15 == 0 {
+			s.populateBoard(currentDay)
+		}
+		return currentDay
+	}
+
+	if err := s.rolloverBoard(currentDay, now); err != nil {
+		slog.Error("day rollover failed", "service_date", currentDay, "error", err)
+		return currentDay
+	}
+	s.populateBoard(today)
+	return today
+}
+
+// rolloverBoard archives every flight on the board under the given service day,
+// along with the changes made to them, and starts a fresh board
+func (s *Server) rolloverBoard(date string, now time.Time) error {
+	var archived []board.Flight
+	err := s.Board.ArchiveAndClear(func(dayFlights []board.Flight) error {
+		archived = dayFlights
+		return s.Archive.Save(date, dayFlights, now)
+	})
+	if err != nil {
+		return err
+	}
+	for _, flight := range archived {
+		s.deleteAlert(flight.FlightNumber, flight.AlertID)
+	}
+
+	// A failed audit archive is retried at the next rollover
+	start, _ := s.serviceDayBounds(s.serviceDateAt(now))
+	if err := s.archiveAudit(start); err != nil {
+		slog.Error("could not archive audit log", "service_date", date, "error", err)
+	}
+
+	slog.Info("archived service day", "service_date", date, "flights", len(archived))
+	return nil
+}
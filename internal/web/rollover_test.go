@@ -0,0 +1,105 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+func TestServiceDateBeforeRolloverIsPreviousDay(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+
+	lateNight := time.Date(2025, 3, 2, 1, 30, 0, 0, denver)
+	if got := serviceDate(lateNight, denver, 3, 0); got != "2025-03-01" {
+		t.Errorf("Expected 1:30 AM to belong to 2025-03-01, got %s", got)
+	}
+
+	morning := time.Date(2025, 3, 2, 3, 0, 0, 0, denver)
+	if got := serviceDate(morning, denver, 3, 0); got != "2025-03-02" {
+		t.Errorf("Expected 3:00 AM to start 2025-03-02, got %s", got)
+	}
+}
+
+func TestRolloverBoardArchivesAndClears(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100", CrewCount: 3})
+	srv.Board.Add(board.Flight{FlightNumber: "DL200", CrewCount: 2})
+
+	if err := srv.rolloverBoard("2025-03-01", time.Now()); err != nil {
+		t.Fatalf("rolloverBoard failed: %v", err)
+	}
+
+	if flights := srv.Board.Sorted(); len(flights) != 0 {
+		t.Errorf("Expected an empty board after rollover, got %d flights", len(flights))
+	}
+
+	day, err := srv.Archive.Load("2025-03-01")
+	if err != nil {
+		t.Fatalf("Archive.Load failed: %v", err)
+	}
+	if len(day.Flights) != 2 {
+		t.Errorf("Expected 2 archived flights, got %d", len(day.Flights))
+	}
+}
+
+func TestRolloverArchivesAuditLog(t *testing.T) {
+	srv := newTestServer(t)
+	fake := srv.Clock.(*clock.Fake)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", CrewCount: 3})
+	srv.recordAudit("desk", flight.ID, "edit", []string{"crew count 2 -> 3"}, fake.Now())
+
+	// The log survives a restart
+	restarted, err := New(srv.Config, stubProvider{}, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := restarted.Audit.Entries(); len(entries) != 1 || entries[0].Username != "desk" {
+		t.Fatalf("Expected the audit log to be reloaded, got %+v", entries)
+	}
+
+	// Rollover moves it into the day's archive
+	restarted.recordAudit("desk", flight.ID, "stage", []string{"stage pending -> completed"}, fake.Now())
+	fake.Advance(20 * time.Hour)
+	if err := restarted.rolloverBoard("2025-03-03", fake.Now()); err != nil {
+		t.Fatal(err)
+	}
+	day, err := restarted.Archive.Load("2025-03-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(day.Audit) != 2 || len(restarted.Audit.Entries()) != 0 {
+		t.Errorf("Expected both changes archived and the log cleared, got %+v and %+v", day.Audit, restarted.Audit.Entries())
+	}
+}
+
+func TestCheckRolloverFollowsClock(t *testing.T) {
+	srv := newTestServer(t)
+	fake := srv.Clock.(*clock.Fake)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100", CrewCount: 3})
+
+	// Late at night the board still belongs to the day that started at 3 AM
+	fake.Set(testStart.Add(16*time.Hour + 50*time.Minute))
+	if day := srv.checkRollover("2025-03-03"); day != "2025-03-03" {
+		t.Errorf("Expected 2:50 AM to stay on 2025-03-03, got %s", day)
+	}
+	if len(srv.Board.Sorted()) != 1 {
+		t.Error("Board should not be cleared before the rollover hour")
+	}
+
+	fake.Advance(10 * time.Minute)
+	if day := srv.checkRollover("2025-03-03"); day != "2025-03-04" {
+		t.Errorf("Expected 3:00 AM to start 2025-03-04, got %s", day)
+	}
+	if len(srv.Board.Sorted()) != 0 {
+		t.Error("Expected an empty board after rollover")
+	}
+	day, err := srv.Archive.Load("2025-03-03")
+	if err != nil {
+		t.Fatalf("Archive.Load failed: %v", err)
+	}
+	if !day.ArchivedAt.Equal(fake.Now()) {
+		t.Errorf("Expected the archive stamped at the clock's time, got %s", day.ArchivedAt)
+	}
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// populateBoard adds today's flight for every schedule that runs on the service
+// date and hasn't been added yet. Schedules whose lookup fails are retried on
+// the next run; removing a populated flight from the board doesn't bring it back.
+// Schedules that ended before the date, like imported one-off days, are
+// dropped.
+func (s *Server) populateBoard(date string) {
+	s.populateLock.Lock()
+	defer s.populateLock.Unlock()
+
+	if pruned, err := s.Schedules.PruneEnded(date); err != nil {
+		slog.Error("failed to save schedules", "error", err)
+	} else if pruned > 0 {
+		slog.Info("removed ended schedules", "service_date", date, "schedules", pruned)
+	}
+
+	for _, schedule := range s.Schedules.List() {
+		if schedule.LastPopulated == date || !schedule.RunsOn(date) {
+			continue
+		}
+
+		flight, err := s.lookupFlight(false, schedule.FlightNumber, schedule.Type, schedule.CrewCount)
+		if err != nil {
+			slog.Warn("could not add scheduled flight", "flight", schedule.FlightNumber, "service_date", date, "error", err)
+			continue
+		}
+
+		flight.Stage = board.StagePending
+		flight.Note = schedule.Note
+		flight.ScheduleID = schedule.ID
+		s.Board.Add(flight)
+
+		if err := s.Schedules.MarkPopulated(schedule.ID, date); err != nil {
+			slog.Error("failed to save schedules", "error", err)
+		}
+	}
+}
+
+// schedulesHandler lists recurring schedules and creates new ones
+func (s *Server) schedulesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.renderTemplate(w, r, "schedules", SchedulesPageData{
+			Schedules: s.Schedules.List(),
+			Today:     s.currentServiceDate(),
+		})
+		return
+	}
+
+	schedule, err := parseScheduleForm(r, s.currentServiceDate())
+	if err == nil {
+		schedule.CreatedBy = s.Auth.CurrentUsername(r)
+		err = s.Schedules.Add(schedule)
+	}
+	if err != nil {
+		s.renderTemplate(w, r, "schedules", SchedulesPageData{
+			Schedules: s.Schedules.List(),
+			Today:     s.currentServiceDate(),
+			Error:     err.Error(),
+		})
+		return
+	}
+
+	// A schedule that already applies today goes straight onto the board
+	s.populateBoard(s.currentServiceDate())
+	http.Redirect(w, r, "/schedules", http.StatusSeeOther)
+}
+
+// deleteScheduleHandler removes a recurring schedule
+func (s *Server) deleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/schedules", http.StatusSeeOther)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	if err := s.Schedules.Delete(id); err != nil {
+		s.renderTemplate(w, r, "schedules", SchedulesPageData{
+			Schedules: s.Schedules.List(),
+			Today:     s.currentServiceDate(),
+			Error:     "Failed to save schedules: " + err.Error(),
+		})
+		return
+	}
+
+	http.Redirect(w, r, "/schedules", http.StatusSeeOther)
+}
+
+// parseScheduleForm reads and validates the new schedule form
+func parseScheduleForm(r *http.Request, today string) (board.RecurringSchedule, error) {
+	flightNumber, flightType, crewCount, err := parseFlightForm(r)
+	if err != nil {
+		return board.RecurringSchedule{}, err
+	}
+
+	schedule := board.RecurringSchedule{
+		FlightNumber: flightNumber,
+		Type:         flightType,
+		CrewCount:    crewCount,
+		Note:         strings.TrimSpace(r.FormValue("note")),
+		StartDate:    r.FormValue("start_date"),
+		EndDate:      r.FormValue("end_date"),
+	}
+
+	for _, value := range r.Form["weekday"] {
+		day, err := strconv.Atoi(value)
+		if err != nil || day < 0 || day > 6 {
+			return board.RecurringSchedule{}, fmt.Errorf("Invalid day of week")
+		}
+		schedule.Weekdays = append(schedule.Weekdays, time.Weekday(day))
+	}
+	if len(schedule.Weekdays) == 0 {
+		return board.RecurringSchedule{}, fmt.Errorf("Select at least one day of the week")
+	}
+	// Monday first, matching the form
+	sort.Slice(schedule.Weekdays, func(i, j int) bool {
+		return (schedule.Weekdays[i]+6)%7 < (schedule.Weekdays[j]+6)%7
+	})
+
+	if schedule.StartDate == "" {
+		schedule.StartDate = today
+	}
+	if _, err := time.Parse("2006-01-02", schedule.StartDate); err != nil {
+		return board.RecurringSchedule{}, fmt.Errorf("Invalid start date")
+	}
+	if schedule.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", schedule.EndDate); err != nil {
+			return board.RecurringSchedule{}, fmt.Errorf("Invalid end date")
+		}
+		if schedule.EndDate < schedule.StartDate {
+			return board.RecurringSchedule{}, fmt.Errorf("End date must be after start date")
+		}
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestParseScheduleForm(t *testing.T) {
+	form := url.Values{}
+	form.Add("flight_number", " ua300 ")
+	form.Add("is_dropoff", "on")
+	form.Add("crew_count", "5")
+	form.Add("weekday", "0")
+	form.Add("weekday", "3")
+	form.Add("start_date", "2025-03-01")
+	form.Add("note", "Contract crew")
+
+	req := httptest.NewRequest("POST", "/schedules", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	schedule, err := parseScheduleForm(req, "2025-03-01")
+	if err != nil {
+		t.Fatalf("parseScheduleForm failed: %v", err)
+	}
+	if schedule.FlightNumber != "UA300" || schedule.Type != "dropoff" || schedule.CrewCount != 5 {
+		t.Errorf("Unexpected schedule: %+v", schedule)
+	}
+	if schedule.WeekdayNames() != "Wed, Sun" {
+		t.Errorf("Expected weekdays 'Wed, Sun', got '%s'", schedule.WeekdayNames())
+	}
+}
+
+func TestParseScheduleFormRejectsEndBeforeStart(t *testing.T) {
+	form := url.Values{}
+	form.Add("flight_number", "UA300")
+	form.Add("is_pickup", "on")
+	form.Add("crew_count", "2")
+	form.Add("weekday", "1")
+	form.Add("start_date", "2025-03-10")
+	form.Add("end_date", "2025-03-01")
+
+	req := httptest.NewRequest("POST", "/schedules", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, err := parseScheduleForm(req, "2025-03-01"); err == nil {
+		t.Error("Expected an end date before the start date to be rejected")
+	}
+}
+
+func TestPopulateBoardSkipsSchedulesAlreadyAdded(t *testing.T) {
+	srv := newTestServer(t)
+	err := srv.Schedules.Add(board.RecurringSchedule{
+		FlightNumber:  "AA100",
+		Weekdays:      []time.Weekday{time.Monday},
+		StartDate:     "2025-03-01",
+		LastPopulated: "2025-03-03",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.populateBoard("2025-03-03")
+
+	if flights := srv.Board.Sorted(); len(flights) != 0 {
+		t.Errorf("Schedule already populated for the day should not add a flight, got %d", len(flights))
+	}
+}
@@ -0,0 +1,261 @@
+// Package web serves the shuttle board and runs the background jobs that
+// keep it current.
+package web
+
+import (
+	"context"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"shuttletracker/internal/auth"
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
+	"shuttletracker/internal/provider"
+)
+
+// Server holds everything the pages and background jobs work with
+type Server struct {
+	Config    config.Config
+	Board     *board.Board
+	Archive   *board.Archive
+	Audit     *board.AuditLog
+	Schedules *board.Schedules
+	Auth      *auth.Store
+	Feeds     *auth.FeedTokens
+	Clock     clock.Clock
+
+	live   provider.Provider // Uncached lookups, used by the poller
+	cached provider.Provider // Lookups for the pages, schedules and importer
+	demo   provider.Provider // Simulated flights for demo accounts
+
+	// airport lists the home airport's arrivals and departures for
+	// suggestions and the airport page, or is nil if the provider can't
+	airport provider.AirportLister
+	// alerter registers push alerts for live flights and reads the ones
+	// posted back, or is nil if no webhook URL is configured
+	alerter provider.Alerter
+
+	// templates holds each page parsed together with the layout and partials, by page name
+	templates map[string]*template.Template
+	// shuttingDown is set when the server starts draining so load balancers stop sending traffic
+	shuttingDown atomic.Bool
+
+	// The most recent import, shown on the import page while it runs in the background
+	lastImport *ImportStatus
+	importLock sync.Mutex
+
+	// populateLock stops two populate runs from adding the same flight twice
+	populateLock sync.Mutex
+
+	// jobs tracks work requests start in the background, like imports.
+	// jobsCtx is cancelled and jobsClosed set when Run's context is, so no
+	// more start, and Run waits for the ones already running.
+	jobsLock   sync.Mutex
+	jobs       sync.WaitGroup
+	jobsClosed bool
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+}
+
+// New creates a server for cfg that looks flights up with lookup and reads
+// the time from clk. It parses the templates and loads the saved schedules
+// and audit log from the data directory.
+func New(cfg config.Config, lookup provider.Provider, clk clock.Clock) (*Server, error) {
+	s := &Server{
+		Config:    cfg,
+		Board:     board.New(),
+		Archive:   board.NewArchive(filepath.Join(cfg.Server.DataDir, "archive")),
+		Audit:     board.NewAuditLog(filepath.Join(cfg.Server.DataDir, "audit.json")),
+		Schedules: board.NewSchedules(filepath.Join(cfg.Server.DataDir, "schedules.json")),
+		Auth:      auth.NewStore(cfg.Auth, clk),
+		Feeds:     auth.NewFeedTokens(filepath.Join(cfg.Server.DataDir, "calendar_tokens.json")),
+		Clock:     clk,
+
+		live:   lookup,
+		cached: provider.NewCache(lookup, cfg.Provider.CacheTTL, clk),
+		demo:   provider.NewDemo(cfg.Location(), clk, cfg.Demo.Seed, cfg.Demo.Speed),
+	}
+
+	s.jobsCtx, s.cancelJobs = context.WithCancel(context.Background())
+
+	if lister, ok := lookup.(provider.AirportLister); ok {
+		s.airport = provider.NewAirportCache(lister, cfg.Provider.AirportTTL, clk)
+	}
+	if alerter, ok := lookup.(provider.Alerter); ok && cfg.Provider.WebhookURL != "" {
+		s.alerter = alerter
+	}
+
+	var err error
+	if s.templates, err = s.parseTemplates(); err != nil {
+		return nil, err
+	}
+	if err := s.Schedules.Load(); err != nil {
+		return nil, err
+	}
+	if err := s.Audit.Load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// route is a path the server answers and the handler behind it
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+}
+
+// routes lists every path the server answers, with the access each needs
+func (s *Server) routes() []route {
+	requireAuth := s.Auth.RequireAuth
+	requireRole := s.Auth.RequireRole
+
+	return []route{
+		{"/login", s.loginHandler},
+		{"/", requireAuth(s.homeHandler)},
+		{"/add", requireAuth(s.addFlightHandler)},
+		{"/edit", requireAuth(s.editFlightHandler)},
+		{"/stage", requireAuth(s.stageHandler)},
+		{"/remove", requireAuth(s.removeFlightHandler)},
+		{"/crew/add", requireAuth(requireRole(s.addCrewHandler, "valet", "desk"))},
+		{"/crew/remove", requireAuth(requireRole(s.removeCrewHandler, "valet", "desk"))},
+		{"/crew/checkin", requireAuth(requireRole(s.checkInCrewHandler, "valet", "desk"))},
+		{"/update-note", requireAuth(s.updateNoteHandler)},
+		{"/flights/suggest", requireAuth(s.suggestHandler)},
+		{"/print", requireAuth(s.printHandler)},
+		{"/print.pdf", requireAuth(s.printPDFHandler)},
+		{"/calendar", requireAuth(s.calendarHandler)},
+		{"/calendar.ics", s.calendarFeedHandler},
+		{"/history", requireAuth(requireRole(s.historyHandler, "desk"))},
+		{"/reports", requireAuth(requireRole(s.reportsHandler, "desk"))},
+		{"/reports.csv", requireAuth(requireRole(s.reportsCSVHandler, "desk"))},
+		{"/schedules", requireAuth(requireRole(s.schedulesHandler, "desk"))},
+		{"/schedules/delete", requireAuth(requireRole(s.deleteScheduleHandler, "desk"))},
+		{"/import", requireAuth(requireRole(s.importHandler, "desk"))},
+		{"/import/confirm", requireAuth(requireRole(s.importConfirmHandler, "desk"))},
+		{"/airport", requireAuth(requireRole(s.airportHandler, "desk"))},
+		{"/airport/track", requireAuth(requireRole(s.trackAirportHandler, "desk"))},
+		{"/logout", requireAuth(s.logoutHandler)},
+		{"/hooks/flightaware", s.flightAwareHookHandler},
+		{"/metrics", s.metricsHandler},
+		{"/healthz", s.healthzHandler},
+		{"/readyz", s.readyzHandler},
+		{"/static/", s.staticHandler().ServeHTTP},
+	}
+}
+
+// Routes lists the pattern of every route Handler registers
+func (s *Server) Routes() []string {
+	var patterns []string
+	for _, route := range s.routes() {
+		patterns = append(patterns, route.pattern)
+	}
+	return patterns
+}
+
+// Handler registers every route and wraps them in the shared middleware
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range s.routes() {
+		mux.HandleFunc(route.pattern, route.handler)
+	}
+	return securityHeaders(withRequestID(s.logRequests(instrumentRequests(mux, mux))))
+}
+
+// NewHTTPServer creates a server with timeouts so slow or idle clients can't tie up connections
+func NewHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// Run keeps the board current until ctx is cancelled: it rolls the board over
+// each service day, refreshes live flights from the provider and plays demo
+// flights forward in the simulator. Once ctx is cancelled it also stops any
+// background jobs and waits for them, so the board can be saved afterwards.
+func (s *Server) Run(ctx context.Context) {
+	stopJobs := context.AfterFunc(ctx, s.closeJobs)
+	defer stopJobs()
+
+	var background sync.WaitGroup
+	background.Add(3)
+	go func() {
+		defer background.Done()
+		s.runRollover(ctx)
+	}()
+	go func() {
+		defer background.Done()
+		s.runPoller(ctx)
+	}()
+	go func() {
+		defer background.Done()
+		s.runDemo(ctx)
+	}()
+	background.Wait()
+	s.closeJobs()
+	s.jobs.Wait()
+}
+
+// goBackground runs fn outside the request that started it, under the
+// server's job context so shutdown stops it and waits for it. It reports
+// false without running fn once the server is shutting down.
+func (s *Server) goBackground(fn func(ctx context.Context)) bool {
+	s.jobsLock.Lock()
+	defer s.jobsLock.Unlock()
+
+	if s.jobsClosed || s.shuttingDown.Load() {
+		return false
+	}
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		fn(s.jobsCtx)
+	}()
+	return true
+}
+
+// closeJobs stops the background jobs and lets no more start
+func (s *Server) closeJobs() {
+	s.jobsLock.Lock()
+	defer s.jobsLock.Unlock()
+
+	s.jobsClosed = true
+	s.cancelJobs()
+}
+
+// StartShutdown marks the server as draining so /readyz starts failing
+func (s *Server) StartShutdown() {
+	s.shuttingDown.Store(true)
+}
+
+// boardPath returns the file the live board is saved to at shutdown
+func (s *Server) boardPath() string {
+	return filepath.Join(s.Config.Server.DataDir, "board.json")
+}
+
+// SaveBoard writes the live board to disk so it survives a restart
+func (s *Server) SaveBoard() error {
+	return s.Board.Save(s.boardPath(), s.currentServiceDate(), s.Clock.Now())
+}
+
+// RestoreBoard picks the board back up from the last shutdown
+func (s *Server) RestoreBoard() error {
+	restored, err := s.Board.Restore(s.boardPath(), s.currentServiceDate(), s.Archive, s.Clock.Now())
+	if err != nil {
+		return err
+	}
+	if restored > 0 {
+		slog.Info("restored board", "flights", restored)
+	}
+	return nil
+}
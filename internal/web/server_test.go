@@ -0,0 +1,42 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
+)
+
+// stubProvider answers every lookup without calling an API
+type stubProvider struct{}
+
+func (stubProvider) Lookup(flightNumber string) (board.Flight, error) {
+	return board.Flight{FlightNumber: flightNumber, Airline: "Test Air", Status: "Scheduled"}, nil
+}
+
+// testStart is a Monday morning in the hotel's default time zone
+var testStart = func() time.Time {
+	denver, _ := time.LoadLocation("America/Denver")
+	return time.Date(2025, 3, 3, 10, 0, 0, 0, denver)
+}()
+
+// newTestServer creates a server with default config, a throwaway data
+// directory and a fake clock stopped at testStart
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	cfg, _, err := config.Load([]string{"-data-dir", t.TempDir()})
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	cfg.Auth.BcryptCost = bcrypt.MinCost
+
+	srv, err := New(cfg, stubProvider{}, clock.NewFake(testStart))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return srv
+}
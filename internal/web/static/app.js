@@ -0,0 +1,68 @@
+// Page behaviour. Inline scripts and handlers are blocked by the content
+// security policy, so everything is attached here.
+
+// Reload the board on the interval the server asks for
+var refreshMillis = parseInt(document.body.dataset.refreshMillis, 10);
+if (refreshMillis > 0) {
+    setTimeout(function() {
+        location.reload();
+    }, refreshMillis);
+}
+
+// Save a flight note as soon as the valet leaves the box
+function saveNote(textarea) {
+    var form = textarea.closest('form');
+    fetch('/update-note', {
+        method: 'POST',
+        body: new FormData(form)
+    }).then(function() {
+        location.reload();
+    });
+}
+
+document.querySelectorAll('.note-input').forEach(function(textarea) {
+    textarea.addEventListener('blur', function() {
+        saveNote(textarea);
+    });
+});
+
+// Offer today's arrivals as a flight number is typed
+var suggestions = document.getElementById('flight-suggestions');
+var suggestTimer;
+document.querySelectorAll('.flight-suggest').forEach(function(input) {
+    input.addEventListener('input', function() {
+        clearTimeout(suggestTimer);
+        var query = input.value.trim();
+        if (!suggestions || query.length < 2) {
+            return;
+        }
+        suggestTimer = setTimeout(function() {
+            fetch('/flights/suggest?q=' + encodeURIComponent(query)).then(function(response) {
+                return response.json();
+            }).then(function(flights) {
+                suggestions.replaceChildren();
+                flights.forEach(function(flight) {
+                    var option = document.createElement('option');
+                    option.value = flight.flight_number;
+                    option.label = flight.airline + ' \u00b7 ' + flight.arrival + ' \u00b7 ' + flight.status;
+                    suggestions.appendChild(option);
+                });
+            });
+        }, 250);
+    });
+});
+
+// Run sheet print button
+var printButton = document.getElementById('print-button');
+if (printButton) {
+    printButton.addEventListener('click', function() {
+        window.print();
+    });
+}
+
+// Select the whole calendar feed address on click so it's easy to copy
+document.querySelectorAll('.feed-url').forEach(function(input) {
+    input.addEventListener('click', function() {
+        input.select();
+    });
+});
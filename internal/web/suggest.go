@@ -0,0 +1,119 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/provider"
+)
+
+// maxSuggestions caps how many flights the add form offers at once
+const maxSuggestions = 10
+
+// Suggestions come from arrivals between suggestBehind ago, for crews that
+// have just landed, and suggestAhead from now. A whole day at a hub runs to
+// many pages of arrivals.
+const (
+	suggestBehind = time.Hour
+	suggestAhead  = 6 * time.Hour
+)
+
+// Suggestion is one flight offered as a flight number is typed
+type Suggestion struct {
+	FlightNumber string `json:"flight_number"`
+	Airline      string `json:"airline"`
+	Arrival      string `json:"arrival"` // Expected arrival for display, e.g. "3:05 PM"
+	Status       string `json:"status"`
+}
+
+// suggestHandler lists the arrivals due around now into the home airport that match
+// what has been typed into a flight number box so far
+func (s *Server) suggestHandler(w http.ResponseWriter, r *http.Request) {
+	user := s.Auth.CurrentUser(r)
+	isDemo := user != nil && user.Role == "demo"
+
+	suggestions := []Suggestion{}
+	for _, flight := range s.suggestFlights(isDemo, r.URL.Query().Get("q")) {
+		suggestions = append(suggestions, Suggestion{
+			FlightNumber: flight.FlightNumber,
+			Airline:      flight.Airline,
+			Arrival:      flight.ExpectedArrival,
+			Status:       flight.Status,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(suggestions)
+}
+
+// airportLister returns where to list the home airport's flights from, or
+// nil if the provider can't list them
+func (s *Server) airportLister(isDemo bool) provider.AirportLister {
+	if isDemo {
+		lister, _ := s.demo.(provider.AirportLister)
+		return lister
+	}
+	return s.airport
+}
+
+// suggestWindow returns the range suggestions are drawn from, rounded to the
+// quarter hour like the airport page so keystrokes share a cached list
+func (s *Server) suggestWindow() (from, to time.Time) {
+	now := s.Clock.Now().Truncate(15 * time.Minute)
+	return now.Add(-suggestBehind), now.Add(suggestAhead)
+}
+
+// suggestFlights returns up to maxSuggestions of the arrivals around now whose
+// flight number starts with query, in either IATA or ICAO form, or whose
+// airline name contains it
+func (s *Server) suggestFlights(isDemo bool, query string) []board.Flight {
+	query = strings.ToUpper(strings.Join(strings.Fields(query), ""))
+	lister := s.airportLister(isDemo)
+	if query == "" || lister == nil || s.Config.Provider.HomeAirport == "" {
+		return nil
+	}
+	normalized, err := board.NormalizeFlightNumber(query)
+	if err != nil {
+		normalized = query
+	}
+
+	from, to := s.suggestWindow()
+	arrivals, _, err := lister.Arrivals(s.Config.Provider.HomeAirport, from, to)
+	if err != nil {
+		slog.Warn("could not list arrivals", "airport", s.Config.Provider.HomeAirport, "error", err)
+		return nil
+	}
+
+	var matches []board.Flight
+	for _, flight := range arrivals {
+		if strings.HasPrefix(flight.FlightNumber, normalized) ||
+			strings.HasPrefix(board.ICAOFlightNumber(flight.FlightNumber), query) ||
+			(len(query) >= 3 && strings.Contains(strings.ToUpper(flight.Airline), query)) {
+			matches = append(matches, flight)
+			if len(matches) == maxSuggestions {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// didYouMean suggests flights close to a number the provider couldn't find,
+// trying shorter and shorter prefixes of it until something matches
+func (s *Server) didYouMean(isDemo bool, flightNumber string) []string {
+	for end := len(flightNumber) - 1; end >= 3; end-- {
+		if matches := s.suggestFlights(isDemo, flightNumber[:end]); len(matches) > 0 {
+			numbers := make([]string, 0, 5)
+			for _, flight := range matches[:min(len(matches), 5)] {
+				numbers = append(numbers, flight.FlightNumber)
+			}
+			return numbers
+		}
+	}
+	return nil
+}
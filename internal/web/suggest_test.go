@@ -0,0 +1,90 @@
+package web
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+)
+
+// stubAirport lists the same arrivals for every airport and day, and no departures
+type stubAirport []board.Flight
+
+func (a stubAirport) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return a, false, nil
+}
+
+func (a stubAirport) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return nil, false, nil
+}
+
+func TestSuggestFlights(t *testing.T) {
+	srv := newTestServer(t)
+	srv.airport = stubAirport{
+		{FlightNumber: "AA100", Airline: "American Airlines"},
+		{FlightNumber: "AA1042", Airline: "American Airlines"},
+		{FlightNumber: "UA12", Airline: "United Airlines"},
+		{FlightNumber: "WN1234", Airline: "Southwest Airlines"},
+	}
+
+	cases := map[string][]string{
+		"aa 10":  {"AA100", "AA1042"},
+		"AAL104": {"AA1042"},
+		"ual":    {"UA12"},
+		"south":  {"WN1234"},
+		"DL":     nil,
+		"":       nil,
+	}
+	for query, want := range cases {
+		var got []string
+		for _, flight := range srv.suggestFlights(false, query) {
+			got = append(got, flight.FlightNumber)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("suggestFlights(%q) = %v, want %v", query, got, want)
+		}
+	}
+
+	if got := srv.didYouMean(false, "AA1043"); !reflect.DeepEqual(got, []string{"AA1042"}) {
+		t.Errorf("Expected AA1042 to be suggested for AA1043, got %v", got)
+	}
+	if got := srv.didYouMean(false, "DL100"); got != nil {
+		t.Errorf("Expected no suggestions for another airline, got %v", got)
+	}
+}
+
+// windowAirport records the range it was last asked to list
+type windowAirport struct {
+	from, to time.Time
+}
+
+func (a *windowAirport) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	a.from, a.to = from, to
+	return nil, false, nil
+}
+
+func (a *windowAirport) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return nil, false, nil
+}
+
+func TestSuggestFlightsListAroundNow(t *testing.T) {
+	srv := newTestServer(t)
+	lister := &windowAirport{}
+	srv.airport = lister
+	srv.Clock.(*clock.Fake).Advance(7 * time.Minute)
+
+	srv.suggestFlights(false, "UA")
+	if !lister.from.Equal(testStart.Add(-time.Hour)) || !lister.to.Equal(testStart.Add(6*time.Hour)) {
+		t.Errorf("Expected arrivals from 9:00 AM to 4:00 PM, got %s to %s", lister.from, lister.to)
+	}
+}
+
+func TestServiceDayBounds(t *testing.T) {
+	srv := newTestServer(t)
+	start, end := srv.serviceDayBounds("2025-03-03")
+	if !start.Equal(testStart.Add(-7*time.Hour)) || !end.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("Expected 3:00 AM to 3:00 AM the next day, got %s to %s", start, end)
+	}
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+// Page templates, the shared layout and partials, and static CSS/JS are
+// compiled into the binary
+//
+//go:embed templates static
+var assets embed.FS
+
+// gateChangeHighlight is how long a flight card points out a new gate
+const gateChangeHighlight = 30 * time.Minute
+
+// templateFuncs are available to every template. renderTemplate swaps in the
+// real cspNonce for each request.
+func (s *Server) templateFuncs() template.FuncMap {
+	localTime := s.Config.Location()
+	return template.FuncMap{
+		"cspNonce": func() string { return "" },
+		// clock formats a time of day in the hotel's time zone, or "" for the zero time
+		"clock": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.In(localTime).Format("3:04 PM")
+		},
+		// stamp formats a date and time in the hotel's time zone
+		"stamp": func(t time.Time) string {
+			return t.In(localTime).Format("Jan 2 3:04 PM")
+		},
+		// flightCard bundles a flight with what the flight-card partial needs to know about the viewer
+		"flightCard": func(flight board.Flight, isDemo bool) FlightCard {
+			gateChanged := !flight.GateChangedAt.IsZero() && s.Clock.Now().Sub(flight.GateChangedAt) < gateChangeHighlight
+			return FlightCard{Flight: flight, IsDemo: isDemo, GateChanged: gateChanged}
+		},
+	}
+}
+
+// assetFS returns the templates and static files, read from disk in dev mode
+// so edits show up on the next request
+func (s *Server) assetFS() fs.FS {
+	if s.Config.UI.DevDir != "" {
+		return os.DirFS(filepath.Join(s.Config.UI.DevDir, "internal", "web"))
+	}
+	return assets
+}
+
+// parseTemplates parses each page in templates/pages with the layout and partials
+func (s *Server) parseTemplates() (map[string]*template.Template, error) {
+	files := s.assetFS()
+	pages, err := fs.Glob(files, "templates/pages/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := map[string]*template.Template{}
+	for _, page := range pages {
+		name := strings.TrimSuffix(path.Base(page), ".html")
+		t, err := template.New(name).Funcs(s.templateFuncs()).ParseFS(files, "templates/layout.html", "templates/partials/*.html", page)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = t
+	}
+	return parsed, nil
+}
+
+// renderTemplate renders a page inside the layout and logs any failure. Output
+// may already be partly written by then, so the error can't be shown to the user.
+func (s *Server) renderTemplate(w http.ResponseWriter, r *http.Request, name string, data any) {
+	pages := s.templates
+	if s.Config.UI.DevDir != "" {
+		reloaded, err := s.parseTemplates()
+		if err != nil {
+			requestLogger(r).Error("template reload failed", "error", err)
+			http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pages = reloaded
+	}
+
+	page, ok := pages[name]
+	if !ok {
+		requestLogger(r).Error("template failed", "template", name, "error", "no such page")
+		http.Error(w, "Page not found", http.StatusInternalServerError)
+		return
+	}
+
+	// Execute a copy so this request's CSP nonce doesn't leak into other requests
+	page, err := page.Clone()
+	if err == nil {
+		page.Funcs(template.FuncMap{"cspNonce": func() string { return cspNonce(r) }})
+		err = page.ExecuteTemplate(w, "layout", data)
+	}
+	if err != nil {
+		requestLogger(r).Error("template failed", "template", name, "error", err)
+	}
+}
+
+// staticHandler serves the CSS and JavaScript under /static/
+func (s *Server) staticHandler() http.Handler {
+	files, err := fs.Sub(s.assetFS(), "static")
+	if err != nil {
+		panic(fmt.Sprintf("static assets missing: %v", err))
+	}
+	return http.StripPrefix("/static/", http.FileServer(http.FS(files)))
+}
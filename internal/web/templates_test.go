@@ -0,0 +1,98 @@
+package web
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestEveryPageParsesWithLayout(t *testing.T) {
+	pages, err := newTestServer(t).parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates failed: %v", err)
+	}
+	for _, name := range []string{"index", "login", "history", "reports", "schedules", "import", "print", "calendar"} {
+		page, ok := pages[name]
+		if !ok {
+			t.Errorf("Missing page %q", name)
+			continue
+		}
+		if page.Lookup("layout") == nil || page.Lookup("flight-card") == nil {
+			t.Errorf("Page %q wasn't parsed with the layout and partials", name)
+		}
+	}
+}
+
+func TestClockUsesHotelTimeZone(t *testing.T) {
+	clock := newTestServer(t).templateFuncs()["clock"].(func(time.Time) string)
+
+	if got := clock(time.Time{}); got != "" {
+		t.Errorf("Expected the zero time to render blank, got %q", got)
+	}
+	// 21:30 UTC is 3:30 PM in Denver during daylight saving time
+	if got := clock(time.Date(2025, 7, 1, 21, 30, 0, 0, time.UTC)); got != "3:30 PM" {
+		t.Errorf("Expected 3:30 PM, got %q", got)
+	}
+}
+
+func TestFlightCardHidesRosterForDemo(t *testing.T) {
+	srv := newTestServer(t)
+	data := PageData{Flights: []board.Flight{{ID: 1, FlightNumber: "AA100", Type: "pickup", CrewCount: 2}}}
+
+	w := httptest.NewRecorder()
+	srv.renderTemplate(w, httptest.NewRequest("GET", "/", nil), "index", data)
+	if !strings.Contains(w.Body.String(), "AA100") || !strings.Contains(w.Body.String(), "Crew roster") {
+		t.Errorf("Expected the flight card with a roster:\n%s", w.Body.String())
+	}
+
+	data.IsDemo = true
+	w = httptest.NewRecorder()
+	srv.renderTemplate(w, httptest.NewRequest("GET", "/", nil), "index", data)
+	if strings.Contains(w.Body.String(), "Crew roster") {
+		t.Error("Demo accounts shouldn't see the crew roster")
+	}
+}
+
+func TestFlightCardShowsDataSource(t *testing.T) {
+	srv := newTestServer(t)
+	data := PageData{Flights: []board.Flight{{ID: 1, FlightNumber: "AA100", Type: "pickup", CrewCount: 2, Source: "aviationstack"}}}
+
+	w := httptest.NewRecorder()
+	srv.renderTemplate(w, httptest.NewRequest("GET", "/", nil), "index", data)
+	if !strings.Contains(w.Body.String(), "Data from aviationstack") {
+		t.Errorf("Expected the card to name where its data came from:\n%s", w.Body.String())
+	}
+}
+
+func TestDevDirReloadsTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.CopyFS(filepath.Join(dir, "internal", "web"), assets); err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer(t)
+	srv.Config.UI.DevDir = dir
+
+	render := func() string {
+		w := httptest.NewRecorder()
+		srv.renderTemplate(w, httptest.NewRequest("GET", "/login", nil), "login", nil)
+		return w.Body.String()
+	}
+	if !strings.Contains(render(), "<title>Login") {
+		t.Fatal("Expected the login page from the dev directory")
+	}
+
+	loginPath := filepath.Join(dir, "internal", "web", "templates", "pages", "login.html")
+	page, _ := os.ReadFile(loginPath)
+	edited := strings.Replace(string(page), "<h1>", "<h1>Edited ", 1)
+	if err := os.WriteFile(loginPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(render(), "Edited ") {
+		t.Error("Expected the edited template without a restart")
+	}
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/config"
+)
+
+func TestSelfSignedCertIsCreatedOnceAndReused(t *testing.T) {
+	cfg := config.ServerConfig{DataDir: t.TempDir(), TLSSelfSigned: true}
+
+	certFile, keyFile, err := TLSFiles(cfg)
+	if err != nil {
+		t.Fatalf("TLSFiles failed: %v", err)
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Fatalf("Generated pair doesn't load: %v", err)
+	}
+	first, _ := os.ReadFile(certFile)
+
+	if _, _, err := TLSFiles(cfg); err != nil {
+		t.Fatalf("Second TLSFiles failed: %v", err)
+	}
+	second, _ := os.ReadFile(certFile)
+	if string(first) != string(second) {
+		t.Error("Expected the saved cert to be reused across restarts")
+	}
+
+	// A cert about to expire is replaced
+	if selfSignedCertValid(certFile, keyFile, time.Now().Add(360*24*time.Hour)) {
+		t.Error("Expected a cert near expiry to need renewal")
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	cases := []struct {
+		httpsAddr, host, want string
+	}{
+		{":8443", "shuttle.local:8080", "https://shuttle.local:8443/print?day=today"},
+		{":443", "10.0.0.5", "https://10.0.0.5/print?day=today"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "http://"+tc.host+"/print?day=today", nil)
+		w := httptest.NewRecorder()
+		RedirectToHTTPS(tc.httpsAddr)(w, req)
+
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != tc.want {
+			t.Errorf("Expected redirect to %s, got %d %s", tc.want, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestSecurityHeadersSendHSTSOnlyOverTLS(t *testing.T) {
+	handler := securityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS should not be sent over plain HTTP")
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" || w.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("Missing security headers: %v", w.Header())
+	}
+
+	req := httptest.NewRequest("GET", "https://shuttle.local/", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if !strings.HasPrefix(w.Header().Get("Strict-Transport-Security"), "max-age=") {
+		t.Errorf("Expected HSTS over HTTPS, got %v", w.Header())
+	}
+}
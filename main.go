@@ -1,185 +1,141 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"html/template"
+	"log/slog"
 	"net/http"
-	"sort"
-	"strconv"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
-)
 
-var flights = []Flight{}
-var nextID = 1
-var tmpl *template.Template
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
+	"shuttletracker/internal/provider"
+	"shuttletracker/internal/web"
+)
 
 func main() {
-	// Initialize HTML templates
-	var err error
-	tmpl, err = template.New("index").Parse(htmlTemplate)
-	if err != nil {
-		panic(err)
+	cfg, printConfig, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	tmpl, err = tmpl.New("login").Parse(loginTemplate)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
-
-	// Register HTTP routes
-	http.HandleFunc("/login", loginHandler)
-	http.HandleFunc("/", requireAuth(homeHandler))
-	http.HandleFunc("/add", requireAuth(addFlightHandler))
-	http.HandleFunc("/remove", requireAuth(removeFlightHandler))
-	http.HandleFunc("/update-note", requireAuth(updateNoteHandler))
-	http.HandleFunc("/logout", requireAuth(logoutHandler))
-
-	fmt.Println("Jacob's Flight Tracker")
-	fmt.Println("Server starting on http://localhost:8080")
-	fmt.Println("\nDemo account: demo / demo123")
-	http.ListenAndServe(":8080", nil)
-}
-
-// homeHandler displays all flights sorted by arrival time
-func homeHandler(w http.ResponseWriter, r *http.Request) {
-	user := getCurrentUser(r)
-	isDemo := user != nil && user.Role == "demo"
-
-	// Sort flights chronologically by expected arrival
-	sortedFlights := make([]Flight, len(flights))
-	copy(sortedFlights, flights)
-	sort.Slice(sortedFlights, func(i, j int) bool {
-		return sortedFlights[i].SortTime.Before(sortedFlights[j].SortTime)
-	})
-
-	tmpl.ExecuteTemplate(w, "index", PageData{
-		Flights: sortedFlights,
-		IsDemo:  isDemo,
-	})
-}
-
-// addFlightHandler processes new flight additions
-func addFlightHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
+	if printConfig {
+		if err := config.Write(os.Stdout, cfg); err != nil {
+			panic(err)
+		}
 		return
 	}
 
-	user := getCurrentUser(r)
-	isDemo := user != nil && user.Role == "demo"
-
-	// Parse form data
-	flightNumber := r.FormValue("flight_number")
-	isPickup := r.FormValue("is_pickup") == "on"
-	isDropoff := r.FormValue("is_dropoff") == "on"
-	crewCount, _ := strconv.Atoi(r.FormValue("crew_count"))
-
-	// Determine operation type
-	flightType := "pickup"
-	if isPickup && isDropoff {
-		flightType = "both"
-	} else if isDropoff {
-		flightType = "dropoff"
+	if err := web.SetupLogging(cfg.Log.Level, cfg.Log.Format); err != nil {
+		panic(err)
 	}
 
-	var flight Flight
-	var err error
+	// Cancelled on SIGINT/SIGTERM to start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Demo users get fake data, real users get live API data
-	if isDemo {
-		flight = createDemoFlight(flightNumber, flightType, crewCount)
-	} else {
-		flight, err = getFlightStatus(flightNumber, flightType, crewCount)
+	// FlightAware first, then the fallback API if one is configured
+	var lookup provider.Provider = provider.NewFlightAware(cfg.Provider.APIKey, cfg.Provider.Timeout, cfg.Location())
+	if cfg.Provider.FallbackURL != "" {
+		fallback, err := provider.NewJSONAPI(cfg.Provider.FallbackName, cfg.Provider.FallbackURL, cfg.Provider.FallbackAPIKey,
+			cfg.Provider.FallbackKeyHeader, cfg.Provider.FallbackFields, cfg.Provider.Timeout, cfg.Location())
 		if err != nil {
-			sortedFlights := make([]Flight, len(flights))
-			copy(sortedFlights, flights)
-			sort.Slice(sortedFlights, func(i, j int) bool {
-				return sortedFlights[i].SortTime.Before(sortedFlights[j].SortTime)
-			})
-			tmpl.ExecuteTemplate(w, "index", PageData{
-				Flights: sortedFlights,
-				Error:   err.Error() + " (Note: Free API tier may not include all flights)",
-				IsDemo:  isDemo,
-			})
-			return
+			fmt.Fprintln(os.Stderr, "provider.fallback_fields:", err)
+			os.Exit(2)
 		}
+		lookup = provider.NewFailover([]provider.Source{
+			{Name: "flightaware", Provider: lookup},
+			{Name: cfg.Provider.FallbackName, Provider: fallback},
+		}, cfg.Provider.FailoverCooldown, clock.Real{})
 	}
-
-	flight.ID = nextID
-	nextID++
-	flights = append(flights, flight)
-	http.Redirect(w, r, "/", http.StatusSeeOther)
-}
-
-// createDemoFlight generates fake flight data for demo accounts
-func createDemoFlight(flightNumber, flightType string, crewCount int) Flight {
-	now := time.Now()
-	mountainTime, _ := time.LoadLocation("America/Denver")
-
-	// Generate arrival time 2-4 hours from now
-	arrivalTime := now.Add(time.Hour * time.Duration(2+nextID%3)).In(mountainTime)
-
-	// Add random delay to some flights
-	delay := 0
-	isDelayed := false
-	if nextID%3 == 0 {
-		delay = 15 + (nextID % 30)
-		isDelayed = true
+	srv, err := web.New(cfg, lookup, clock.Real{})
+	if err != nil {
+		slog.Error("failed to start", "error", err)
+		os.Exit(1)
 	}
 
-	airlines := []string{"American Airlines", "Delta Air Lines", "United Airlines", "Southwest Airlines"}
-	statuses := []string{"scheduled", "active"}
-
-	return Flight{
-		FlightNumber:     flightNumber,
-		Airline:          airlines[nextID%len(airlines)],
-		Status:           statuses[nextID%len(statuses)],
-		ScheduledArrival: arrivalTime.Format("3:04 PM"),
-		ExpectedArrival:  arrivalTime.Add(time.Duration(delay) * time.Minute).Format("3:04 PM"),
-		Delay:            delay,
-		IsDelayed:        isDelayed,
-		Type:             flightType,
-		CrewCount:        crewCount,
-		Note:             "",
-		SortTime:         arrivalTime.Add(time.Duration(delay) * time.Minute),
+	// Pick the board back up from the last shutdown
+	if err := srv.RestoreBoard(); err != nil {
+		panic(err)
 	}
-}
 
-// removeFlightHandler deletes a flight from the tracking list
-func removeFlightHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
-		return
-	}
+	// Roll the board over each service day and keep expected times current
+	background := make(chan struct{})
+	go func() {
+		defer close(background)
+		srv.Run(ctx)
+	}()
 
-	id, _ := strconv.Atoi(r.FormValue("id"))
+	server := web.NewHTTPServer(cfg.Server.Addr, srv.Handler())
+	serverErr := make(chan error, 2)
 
-	for i, flight := range flights {
-		if flight.ID == id {
-			flights = append(flights[:i], flights[i+1:]...)
-			break
+	if cfg.Server.TLSEnabled() {
+		certFile, keyFile, err := web.TLSFiles(cfg.Server)
+		if err != nil {
+			panic(err)
 		}
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		go func() {
+			serverErr <- server.ListenAndServeTLS(certFile, keyFile)
+		}()
+	} else {
+		go func() {
+			serverErr <- server.ListenAndServe()
+		}()
 	}
 
-	http.Redirect(w, r, "/", http.StatusSeeOther)
-}
+	// Optionally send anyone still using plain HTTP over to HTTPS
+	var redirectServer *http.Server
+	if cfg.Server.HTTPRedirectAddr != "" {
+		redirectServer = web.NewHTTPServer(cfg.Server.HTTPRedirectAddr, web.RedirectToHTTPS(cfg.Server.Addr))
+		go func() {
+			serverErr <- redirectServer.ListenAndServe()
+		}()
+	}
+	slog.Info("Jacob's Flight Tracker starting", "addr", cfg.Server.Addr, "https", cfg.Server.TLSEnabled(), "timezone", cfg.Service.Timezone, "demo_account", "demo")
 
-// updateNoteHandler saves notes for individual flights
-func updateNoteHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
-		return
+	select {
+	case err := <-serverErr:
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
 	}
 
-	id, _ := strconv.Atoi(r.FormValue("id"))
-	note := r.FormValue("note")
+	// A second signal kills the process straight away
+	stop()
+	slog.Info("shutting down", "timeout", cfg.Server.ShutdownTimeout, "delay", cfg.Server.ShutdownDelay)
+	srv.StartShutdown()
 
-	for i := range flights {
-		if flights[i].ID == id {
-			flights[i].Note = note
-			break
-		}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Keep serving while /readyz fails so load balancers notice before
+	// requests are turned away; the delay counts towards the timeout
+	select {
+	case <-shutdownCtx.Done():
+	case <-time.After(cfg.Server.ShutdownDelay):
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("requests did not drain in time", "error", err)
+	}
+	if redirectServer != nil {
+		redirectServer.Shutdown(shutdownCtx)
 	}
 
-	w.WriteHeader(http.StatusOK)
+	// Wait for the poller and rollover so the board isn't changing while we save it
+	<-background
+	if err := srv.SaveBoard(); err != nil {
+		slog.Error("failed to save board", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("shutdown complete")
 }
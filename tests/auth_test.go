@@ -1,21 +1,23 @@
-package main
+package tests
 
 import (
-	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/auth"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
 )
 
 func TestLoginHandlerGET(t *testing.T) {
-	initTemplates()
+	srv := newTestServer(t)
 
-	req := httptest.NewRequest("GET", "/login", nil)
-	w := httptest.NewRecorder()
-
-	loginHandler(w, req)
+	w := get(srv, "/login", "")
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", w.Code)
@@ -28,17 +30,13 @@ func TestLoginHandlerGET(t *testing.T) {
 }
 
 func TestLoginHandlerPOSTSuccess(t *testing.T) {
-	initTemplates()
+	srv := newTestServer(t)
 
 	form := url.Values{}
 	form.Add("username", "demo")
 	form.Add("password", "demo123")
 
-	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	w := httptest.NewRecorder()
-
-	loginHandler(w, req)
+	w := post(srv, "/login", "", form)
 
 	if w.Code != http.StatusSeeOther {
 		t.Errorf("Expected redirect (303), got %d", w.Code)
@@ -63,17 +61,13 @@ func TestLoginHandlerPOSTSuccess(t *testing.T) {
 }
 
 func TestLoginHandlerPOSTFailure(t *testing.T) {
-	initTemplates()
+	srv := newTestServer(t)
 
 	form := url.Values{}
 	form.Add("username", "demo")
 	form.Add("password", "wrongpassword")
 
-	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	w := httptest.NewRecorder()
-
-	loginHandler(w, req)
+	w := post(srv, "/login", "", form)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", w.Code)
@@ -86,13 +80,14 @@ func TestLoginHandlerPOSTFailure(t *testing.T) {
 }
 
 func TestRequireAuthMiddleware(t *testing.T) {
+	store := newTestStore()
 	called := false
 	testHandler := func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		w.WriteHeader(http.StatusOK)
 	}
 
-	protectedHandler := requireAuth(testHandler)
+	protectedHandler := store.RequireAuth(testHandler)
 
 	// Test without session - should redirect
 	req := httptest.NewRequest("GET", "/", nil)
@@ -108,7 +103,7 @@ func TestRequireAuthMiddleware(t *testing.T) {
 
 	// Test with valid session
 	called = false
-	sessionID := createSession("testuser")
+	sessionID := store.CreateSession("testuser")
 	req = httptest.NewRequest("GET", "/", nil)
 	req.AddCookie(&http.Cookie{
 		Name:  "session_id",
@@ -123,7 +118,8 @@ func TestRequireAuthMiddleware(t *testing.T) {
 }
 
 func TestGetCurrentUser(t *testing.T) {
-	sessionID := createSession("valet")
+	store := newTestStore()
+	sessionID := store.CreateSession("valet")
 
 	req := httptest.NewRequest("GET", "/", nil)
 	req.AddCookie(&http.Cookie{
@@ -131,9 +127,9 @@ func TestGetCurrentUser(t *testing.T) {
 		Value: sessionID,
 	})
 
-	user := getCurrentUser(req)
+	user := store.CurrentUser(req)
 	if user == nil {
-		t.Fatal("getCurrentUser returned nil for valid session")
+		t.Fatal("CurrentUser returned nil for valid session")
 	}
 
 	if user.Username != "valet" {
@@ -145,14 +141,7 @@ func TestGetCurrentUser(t *testing.T) {
 	}
 }
 
-func initTemplates() {
-	var err error
-	tmpl, err = template.New("index").Parse(htmlTemplate)
-	if err != nil {
-		panic(err)
-	}
-	tmpl, err = tmpl.New("login").Parse(loginTemplate)
-	if err != nil {
-		panic(err)
-	}
+// newTestStore creates the built-in accounts with cheap password hashes
+func newTestStore() *auth.Store {
+	return auth.NewStore(config.AuthConfig{BcryptCost: bcrypt.MinCost, DemoPassword: "demo123"}, clock.Real{})
 }
@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCalendarFeedRequiresToken(t *testing.T) {
+	srv := newTestServer(t)
+
+	token, err := srv.Feeds.Token("valet", false)
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if again, _ := srv.Feeds.Token("valet", false); again != token {
+		t.Error("Expected the same token until it is regenerated")
+	}
+
+	w := get(srv, "/calendar.ics?token="+token, "")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("Expected a calendar for a valid token, got %d", w.Code)
+	}
+
+	replaced, _ := srv.Feeds.Token("valet", true)
+	w = get(srv, "/calendar.ics?token="+token, "")
+	if replaced == token || w.Code != http.StatusNotFound {
+		t.Errorf("Expected the old token to stop working after regenerating, got %d", w.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"shuttletracker/internal/board"
+)
+
+func TestCrewRosterDerivesCountAndChecksIn(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 5})
+	id := strconv.Itoa(flight.ID)
+	sessionID := srv.Auth.CreateSession("valet")
+
+	for _, name := range []string{"Ana Lopez", "Sam Chen"} {
+		form := url.Values{}
+		form.Add("id", id)
+		form.Add("name", name)
+		form.Add("position", "Flight Attendant")
+		form.Add("room", "214")
+		w := post(srv, "/crew/add", sessionID, form)
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("Expected redirect (303), got %d", w.Code)
+		}
+	}
+
+	flight, _ = srv.Board.Get(flight.ID)
+	if len(flight.Crew) != 2 || flight.CrewCount != 2 {
+		t.Fatalf("Expected 2 rostered crew and crew count 2, got %d and %d", len(flight.Crew), flight.CrewCount)
+	}
+	if flight.Crew[0].ID == flight.Crew[1].ID {
+		t.Error("Crew members should get distinct IDs")
+	}
+
+	form := url.Values{}
+	form.Add("id", id)
+	form.Add("member", strconv.Itoa(flight.Crew[1].ID))
+	post(srv, "/crew/checkin", sessionID, form)
+
+	flight, _ = srv.Board.Get(flight.ID)
+	if flight.CheckedInCount() != 1 || !flight.Crew[1].CheckedIn {
+		t.Errorf("Expected Sam Chen to be checked in, got %+v", flight.Crew)
+	}
+
+	post(srv, "/crew/remove", sessionID, form)
+	flight, _ = srv.Board.Get(flight.ID)
+	if len(flight.Crew) != 1 || flight.CrewCount != 1 {
+		t.Errorf("Expected 1 crew member after removal, got %d (count %d)", len(flight.Crew), flight.CrewCount)
+	}
+}
+
+func TestCrewRosterRequiresNameAndPosition(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 1})
+	sessionID := srv.Auth.CreateSession("valet")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("name", "Ana Lopez")
+	form.Add("position", "Pilot in command")
+	w := post(srv, "/crew/add", sessionID, form)
+
+	if !strings.Contains(w.Body.String(), "Choose a crew position") {
+		t.Error("Expected an invalid position to be rejected")
+	}
+	if flight, _ = srv.Board.Get(flight.ID); len(flight.Crew) != 0 {
+		t.Error("Rejected crew member should not be added")
+	}
+}
+
+func TestCrewRosterHiddenFromDemo(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 1,
+		Crew: []board.CrewMember{{ID: 1, Name: "Ana Lopez", Position: "Captain", Phone: "555-0100"}}})
+
+	for _, tc := range []struct {
+		username string
+		visible  bool
+	}{{"valet", true}, {"demo", false}} {
+		w := get(srv, "/", srv.Auth.CreateSession(tc.username))
+
+		if got := strings.Contains(w.Body.String(), "Ana Lopez"); got != tc.visible {
+			t.Errorf("Roster visible to %s = %v, want %v", tc.username, got, tc.visible)
+		}
+	}
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("name", "Intruder")
+	form.Add("position", "Other")
+	w := post(srv, "/crew/add", srv.Auth.CreateSession("demo"), form)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected demo to be forbidden from editing the roster, got %d", w.Code)
+	}
+}
@@ -0,0 +1,372 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestE2EEachRoleSignsInAndSeesItsPages(t *testing.T) {
+	h := newHarness(t)
+
+	cases := []struct {
+		username string
+		desk     bool // May open the desk-only pages
+	}{{"valet", false}, {"desk", true}, {"demo", false}}
+
+	for _, tc := range cases {
+		b := h.login(tc.username)
+
+		w := b.get("/")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Today's Flights") {
+			t.Errorf("%s: expected the board, got %d", tc.username, w.Code)
+		}
+		for _, page := range []string{"/print", "/calendar"} {
+			if w := b.get(page); w.Code != http.StatusOK {
+				t.Errorf("%s: expected %s to load, got %d", tc.username, page, w.Code)
+			}
+		}
+		for _, page := range []string{"/history", "/reports", "/schedules", "/import", "/airport"} {
+			want := http.StatusForbidden
+			if tc.desk {
+				want = http.StatusOK
+			}
+			if w := b.get(page); w.Code != want {
+				t.Errorf("%s: expected %d from %s, got %d", tc.username, want, page, w.Code)
+			}
+		}
+
+		// Signing in again goes straight to the board
+		if w := b.get("/login"); w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+			t.Errorf("%s: expected /login to redirect a signed-in user to the board, got %d", tc.username, w.Code)
+		}
+
+		b.get("/logout")
+		if w := b.get("/"); w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login" {
+			t.Errorf("%s: expected the session to end at logout, got %d", tc.username, w.Code)
+		}
+	}
+}
+
+func TestE2EProtectedRoutesRedirectToLogin(t *testing.T) {
+	h := newHarness(t)
+	anon := h.anonymous()
+
+	for _, route := range []string{"/", "/print", "/print.pdf", "/calendar", "/history", "/reports", "/schedules", "/import"} {
+		w := anon.get(route)
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login" {
+			t.Errorf("%s: expected a redirect to /login, got %d %s", route, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	// A forged or expired session is treated the same as none
+	forged := &browser{h: h, session: &http.Cookie{Name: "session_id", Value: "not-a-session"}}
+	if w := forged.get("/"); w.Code != http.StatusSeeOther {
+		t.Errorf("Expected an unknown session to be sent to /login, got %d", w.Code)
+	}
+
+	w := anon.post("/add", url.Values{"flight_number": {"AA100"}, "crew_count": {"2"}, "is_pickup": {"on"}})
+	if w.Code != http.StatusSeeOther || len(h.srv.Board.Sorted()) != 0 {
+		t.Errorf("Expected an anonymous add to be turned away, got %d and %d flights", w.Code, len(h.srv.Board.Sorted()))
+	}
+}
+
+func TestE2EFlightLifecycleThroughForms(t *testing.T) {
+	h := newHarness(t)
+	valet := h.login("valet")
+
+	w := valet.post("/add", url.Values{"flight_number": {"aa100"}, "crew_count": {"3"}, "is_pickup": {"on"}})
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected redirect (303) after adding, got %d", w.Code)
+	}
+	flight, found := h.flight("AA100")
+	if !found {
+		t.Fatal("Added flight is not on the board")
+	}
+	if flight.Airline != "Test Air" || flight.CrewCount != 3 || flight.Type != "pickup" || flight.CurrentStage() != board.StagePending {
+		t.Errorf("Unexpected flight after adding: %+v", flight)
+	}
+	if !flight.SortTime.Equal(harnessStart.Add(2 * time.Hour)) {
+		t.Errorf("Expected the provider's arrival time, got %s", flight.SortTime)
+	}
+	body := valet.get("/").Body.String()
+	for _, want := range []string{"AA100", "Test Air", "3 crew", "12:00 PM"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Board is missing %q", want)
+		}
+	}
+
+	id := strconv.Itoa(flight.ID)
+	h.clock.Advance(5 * time.Minute)
+	valet.post("/edit", url.Values{"id": {id}, "flight_number": {"AA100"}, "crew_count": {"4"}, "is_pickup": {"on"}, "is_dropoff": {"on"}})
+	flight, _ = h.flight("AA100")
+	if flight.CrewCount != 4 || flight.Type != "both" {
+		t.Errorf("Edit not applied: %+v", flight)
+	}
+	if flight.LastEditedBy != "valet" || !flight.LastEditedAt.Equal(h.clock.Now()) {
+		t.Errorf("Expected the edit stamped by valet at the clock's time, got %s at %s", flight.LastEditedBy, flight.LastEditedAt)
+	}
+	if !strings.Contains(valet.get("/").Body.String(), "Edited by valet at 10:05 AM") {
+		t.Error("Board doesn't show who last edited the flight")
+	}
+
+	h.clock.Advance(10 * time.Minute)
+	valet.post("/stage", url.Values{"id": {id}, "stage": {board.StageDispatched}})
+	flight, _ = h.flight("AA100")
+	last := flight.LastStageChange()
+	if flight.CurrentStage() != board.StageDispatched || last == nil || !last.Time.Equal(h.clock.Now()) {
+		t.Errorf("Expected dispatch recorded at the clock's time, got %+v", last)
+	}
+
+	// Skipping ahead is refused and shown on the board
+	w = valet.post("/stage", url.Values{"id": {id}, "stage": {board.StageCompleted}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `class="error"`) {
+		t.Errorf("Expected an invalid stage change to be reported, got %d", w.Code)
+	}
+
+	if w := valet.post("/update-note", url.Values{"id": {id}, "note": {"Door 4"}}); w.Code != http.StatusOK {
+		t.Errorf("Expected the note to save, got %d", w.Code)
+	}
+	if !strings.Contains(valet.get("/").Body.String(), "Door 4") {
+		t.Error("Board doesn't show the saved note")
+	}
+
+	entries := h.srv.Audit.Entries()
+	if len(entries) != 2 || entries[0].Action != "edit" || entries[1].Action != "stage" {
+		t.Errorf("Expected an edit and a stage change in the audit log, got %+v", entries)
+	}
+
+	valet.post("/remove", url.Values{"id": {id}})
+	if _, found := h.flight("AA100"); found {
+		t.Error("Removed flight is still on the board")
+	}
+	if strings.Contains(valet.get("/").Body.String(), `<div class="flight-number">AA100</div>`) {
+		t.Error("Removed flight is still shown")
+	}
+}
+
+func TestE2EProviderErrorShownOnBoard(t *testing.T) {
+	h := newHarness(t)
+	valet := h.login("valet")
+
+	w := valet.post("/add", url.Values{"flight_number": {"XX1"}, "crew_count": {"2"}, "is_pickup": {"on"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "flight XX1 not found") {
+		t.Errorf("Expected the lookup error on the board, got %d", w.Code)
+	}
+	if len(h.srv.Board.Sorted()) != 0 {
+		t.Error("A flight that couldn't be looked up should not be added")
+	}
+}
+
+func TestE2EFlightNumberSuggestions(t *testing.T) {
+	h := newHarness(t)
+	for i, number := range []string{"AA100", "AA1042", "UA12"} {
+		arrival := harnessStart.Add(time.Duration(i+1) * time.Hour)
+		h.provider.set(board.Flight{FlightNumber: number, Airline: "Test Air", Status: "Scheduled", ExpectedArrival: arrival.Format("3:04 PM"), SortTime: arrival})
+	}
+	valet := h.login("valet")
+
+	var suggestions []struct {
+		FlightNumber string `json:"flight_number"`
+		Arrival      string `json:"arrival"`
+	}
+	w := valet.get("/flights/suggest?q=aal+10")
+	if err := json.Unmarshal(w.Body.Bytes(), &suggestions); err != nil || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected JSON suggestions, got %q (%v)", w.Body.String(), err)
+	}
+	if len(suggestions) != 2 || suggestions[0].FlightNumber != "AA100" || suggestions[0].Arrival != "11:00 AM" || suggestions[1].FlightNumber != "AA1042" {
+		t.Errorf("Expected AA100 and AA1042, got %+v", suggestions)
+	}
+	if w := h.anonymous().get("/flights/suggest?q=AA"); w.Code != http.StatusSeeOther {
+		t.Errorf("Expected suggestions to need a session, got %d", w.Code)
+	}
+
+	// However the number is typed, the board gets the IATA form
+	valet.post("/add", url.Values{"flight_number": {"aal 0100"}, "crew_count": {"2"}, "is_pickup": {"on"}})
+	if _, found := h.flight("AA100"); !found {
+		t.Errorf("Expected aal 0100 to be added as AA100, got %+v", h.srv.Board.Sorted())
+	}
+
+	// Demo accounts get suggestions from the simulator
+	demo := h.login("demo")
+	w = demo.get("/flights/suggest?q=a")
+	if err := json.Unmarshal(w.Body.Bytes(), &suggestions); err != nil || len(suggestions) == 0 {
+		t.Errorf("Expected simulated suggestions for the demo account, got %q", w.Body.String())
+	}
+}
+
+func TestE2ETrackFlightsFromAirportBoard(t *testing.T) {
+	h := newHarness(t)
+	arrival, departure := harnessStart.Add(time.Hour), harnessStart.Add(2*time.Hour)
+	h.provider.set(board.Flight{FlightNumber: "AA100", Airline: "American Airlines", Status: "Scheduled", ExpectedArrival: "11:00 AM", SortTime: arrival})
+	h.provider.set(board.Flight{FlightNumber: "UA12", Airline: "United Airlines", Status: "Scheduled", ExpectedArrival: "11:00 AM", SortTime: arrival})
+	h.provider.set(board.Flight{FlightNumber: "DL300", Airline: "Delta Air Lines", Status: "Scheduled", ExpectedArrival: "12:00 PM", SortTime: departure, Departing: true})
+	desk := h.login("desk")
+
+	// Already tracked flights can't be ticked again
+	desk.post("/add", url.Values{"flight_number": {"UA12"}, "crew_count": {"2"}, "is_pickup": {"on"}})
+
+	body := desk.get("/airport?hours=4").Body.String()
+	for _, want := range []string{"KDEN Arrivals", `value="arrival:AA100"`, `value="departure:DL300"`, "United Airlines", "On board"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Airport page is missing %q", want)
+		}
+	}
+	if strings.Contains(body, `value="arrival:UA12"`) {
+		t.Error("A flight already on the board should not be offered again")
+	}
+
+	w := desk.post("/airport/track", url.Values{
+		"hours":                {"4"},
+		"track":                {"arrival:AA100", "departure:DL300"},
+		"crew_arrival:AA100":   {"3"},
+		"crew_departure:DL300": {"2"},
+		"crew_arrival:UA12":    {"9"},
+	})
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect to the board, got %d: %s", w.Code, w.Body.String())
+	}
+	pickup, found := h.flight("AA100")
+	if !found || pickup.Type != "pickup" || pickup.CrewCount != 3 || pickup.Departing || pickup.CurrentStage() != board.StagePending {
+		t.Errorf("Unexpected arrival on the board: %+v", pickup)
+	}
+	dropoff, found := h.flight("DL300")
+	if !found || dropoff.Type != "dropoff" || dropoff.CrewCount != 2 || !dropoff.Departing || !dropoff.SortTime.Equal(departure) {
+		t.Errorf("Unexpected departure on the board: %+v", dropoff)
+	}
+	if !strings.Contains(desk.get("/").Body.String(), "departs &middot; scheduled") {
+		t.Error("Board doesn't mark the departing flight")
+	}
+
+	w = desk.post("/airport/track", url.Values{"hours": {"4"}})
+	if !strings.Contains(w.Body.String(), "Tick the flights carrying crews first") {
+		t.Error("Expected an error when nothing is ticked")
+	}
+	if w := h.login("valet").get("/airport"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected the airport page to be desk only, got %d", w.Code)
+	}
+}
+
+func TestE2EDelayedFlightFromProvider(t *testing.T) {
+	h := newHarness(t)
+	arrival := harnessStart.Add(90 * time.Minute)
+	h.provider.set(board.Flight{
+		FlightNumber:     "DL200",
+		Airline:          "Delta Air Lines",
+		Status:           "En Route / Delayed",
+		ScheduledArrival: arrival.Add(-25 * time.Minute).Format("3:04 PM"),
+		ExpectedArrival:  arrival.Format("3:04 PM"),
+		Delay:            25,
+		IsDelayed:        true,
+		SortTime:         arrival,
+	})
+	desk := h.login("desk")
+
+	desk.post("/add", url.Values{"flight_number": {"DL200"}, "crew_count": {"2"}, "is_dropoff": {"on"}})
+	body := desk.get("/").Body.String()
+	for _, want := range []string{"Delta Air Lines", "+25 min delay", "11:30 AM", "scheduled: 11:05 AM"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Board is missing %q", want)
+		}
+	}
+
+	// The run sheet leaves 20 minutes before the expected arrival
+	sheet := desk.get("/print").Body.String()
+	if !strings.Contains(sheet, "Shuttle Run Sheet &ndash; 2025-03-03") || !strings.Contains(sheet, "11:10 AM") {
+		t.Errorf("Run sheet doesn't show the service day and leave-by time:\n%s", sheet)
+	}
+}
+
+func TestE2EServiceDayFollowsClock(t *testing.T) {
+	h := newHarness(t)
+	desk := h.login("desk")
+
+	// Before the 3 AM rollover the night still belongs to the previous day
+	h.clock.Set(harnessStart.Add(16*time.Hour + 30*time.Minute))
+	if sheet := desk.get("/print").Body.String(); !strings.Contains(sheet, "Shuttle Run Sheet &ndash; 2025-03-03") {
+		t.Error("Expected 2:30 AM to still be on the 2025-03-03 service day")
+	}
+
+	h.clock.Advance(time.Hour)
+	if sheet := desk.get("/print").Body.String(); !strings.Contains(sheet, "Shuttle Run Sheet &ndash; 2025-03-04") {
+		t.Error("Expected 3:30 AM to start the 2025-03-04 service day")
+	}
+}
+
+func TestE2ESignedOutAfterSessionExpires(t *testing.T) {
+	h := newHarness(t)
+	valet := h.login("valet")
+
+	h.clock.Advance(6 * 24 * time.Hour)
+	if w := valet.get("/"); w.Code != http.StatusOK {
+		t.Errorf("Expected the session to last six days, got %d", w.Code)
+	}
+
+	h.clock.Advance(24 * time.Hour)
+	if w := valet.get("/"); w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login" {
+		t.Errorf("Expected an expired session to be sent to /login, got %d", w.Code)
+	}
+}
+
+func TestE2EConcurrentSessions(t *testing.T) {
+	h := newHarness(t)
+
+	const users = 10
+	browsers := make([]*browser, users)
+	var wg sync.WaitGroup
+	for i := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := h.login("valet")
+			browsers[i] = b
+			b.post("/add", url.Values{"flight_number": {fmt.Sprintf("UA%d", 300+i)}, "crew_count": {"1"}, "is_pickup": {"on"}})
+		}()
+	}
+	wg.Wait()
+
+	flights := h.srv.Board.Sorted()
+	if len(flights) != users {
+		t.Fatalf("Expected %d flights from concurrent sessions, got %d", users, len(flights))
+	}
+	ids := map[int]bool{}
+	for _, flight := range flights {
+		ids[flight.ID] = true
+	}
+	if len(ids) != users {
+		t.Errorf("Expected distinct flight IDs, got %v", ids)
+	}
+	if h.srv.Auth.ActiveSessions() != users {
+		t.Errorf("Expected %d sessions, got %d", users, h.srv.Auth.ActiveSessions())
+	}
+
+	// Signing out one session leaves the others signed in
+	browsers[0].get("/logout")
+	if w := browsers[0].get("/"); w.Code != http.StatusSeeOther {
+		t.Errorf("Expected the signed-out session to be refused, got %d", w.Code)
+	}
+	if w := browsers[1].get("/"); w.Code != http.StatusOK {
+		t.Errorf("Expected other sessions to stay signed in, got %d", w.Code)
+	}
+}
+
+func TestE2EDemoBoardAutoRefreshes(t *testing.T) {
+	h := newHarness(t, "-demo-speed", "60")
+
+	demo := h.login("demo").get("/").Body.String()
+	if !strings.Contains(demo, `data-refresh-millis="5000"`) {
+		t.Errorf("Expected the demo board to reload as often as the simulator ticks")
+	}
+	desk := h.login("desk").get("/").Body.String()
+	if !strings.Contains(desk, `data-refresh-millis="300000"`) {
+		t.Errorf("Expected the desk board to reload at the configured interval")
+	}
+}
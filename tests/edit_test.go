@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"shuttletracker/internal/board"
+)
+
+func TestEditFlightKeepsNoteAndRecordsAudit(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 2, Note: "Gate B"})
+	sessionID := srv.Auth.CreateSession("demo")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("flight_number", "AA100")
+	form.Add("is_pickup", "on")
+	form.Add("is_dropoff", "on")
+	form.Add("crew_count", "4")
+
+	w := post(srv, "/edit", sessionID, form)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected redirect (303), got %d", w.Code)
+	}
+
+	edited, _ := srv.Board.Get(flight.ID)
+	if edited.CrewCount != 4 {
+		t.Errorf("Expected crew count 4, got %d", edited.CrewCount)
+	}
+	if edited.Type != "both" {
+		t.Errorf("Expected type 'both', got '%s'", edited.Type)
+	}
+	if edited.Note != "Gate B" {
+		t.Errorf("Edit should keep the note, got '%s'", edited.Note)
+	}
+	entries := srv.Audit.Entries()
+	if len(entries) != 1 || len(entries[0].Changes) != 2 {
+		t.Fatalf("Expected one audit entry with two changes, got %+v", entries)
+	}
+	if entries[0].Username != "demo" {
+		t.Errorf("Expected audit username 'demo', got '%s'", entries[0].Username)
+	}
+}
+
+func TestEditFlightNewNumberResolvesAgain(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Airline: "Old Airline", Type: "pickup", CrewCount: 2, Note: "Room 12"})
+	sessionID := srv.Auth.CreateSession("valet")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("flight_number", "dl200")
+	form.Add("is_pickup", "on")
+	form.Add("crew_count", "2")
+
+	post(srv, "/edit", sessionID, form)
+
+	edited, found := srv.Board.Get(flight.ID)
+	if !found {
+		t.Fatal("Edit should keep the flight's ID")
+	}
+	if edited.FlightNumber != "DL200" {
+		t.Errorf("Expected flight number DL200, got %s", edited.FlightNumber)
+	}
+	if edited.Airline != "Test Air" {
+		t.Errorf("Changing the flight number should look the flight up again, got airline %q", edited.Airline)
+	}
+	if edited.Note != "Room 12" {
+		t.Errorf("Edit should keep the note, got '%s'", edited.Note)
+	}
+}
+
+func TestEditDepartingFlightNewNumberLooksUpTheDeparture(t *testing.T) {
+	h := newHarness(t)
+	flight := h.srv.Board.Add(board.Flight{FlightNumber: "DL300", Type: "dropoff", CrewCount: 2, Departing: true, SortTime: harnessStart.Add(3 * time.Hour)})
+	sessionID := h.srv.Auth.CreateSession("desk")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("flight_number", "DL301")
+	form.Add("is_dropoff", "on")
+	form.Add("crew_count", "2")
+	post(h.srv, "/edit", sessionID, form)
+
+	edited, _ := h.srv.Board.Get(flight.ID)
+	if edited.FlightNumber != "DL301" || !edited.Departing || !edited.SortTime.Equal(harnessStart.Add(time.Hour)) {
+		t.Errorf("Expected DL301 timed by its departure, got %+v", edited)
+	}
+}
+
+func TestEditFlightRejectsInvalidCrewCount(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 2})
+	sessionID := srv.Auth.CreateSession("demo")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("flight_number", "AA100")
+	form.Add("is_pickup", "on")
+	form.Add("crew_count", "0")
+
+	w := post(srv, "/edit", sessionID, form)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Crew count must be") {
+		t.Error("Validation error not displayed")
+	}
+	if edited, _ := srv.Board.Get(flight.ID); edited.CrewCount != 2 {
+		t.Errorf("Invalid edit should not change the flight, got crew count %d", edited.CrewCount)
+	}
+}
+
+func TestEditFlightRejectsCrewCountOffRoster(t *testing.T) {
+	srv := newTestServer(t)
+	flight := srv.Board.Add(board.Flight{FlightNumber: "AA100", Type: "pickup", CrewCount: 2,
+		Crew: []board.CrewMember{{ID: 1, Name: "Ana Lopez"}, {ID: 2, Name: "Sam Chen"}}})
+	sessionID := srv.Auth.CreateSession("desk")
+
+	form := url.Values{}
+	form.Add("id", strconv.Itoa(flight.ID))
+	form.Add("flight_number", "AA100")
+	form.Add("is_dropoff", "on")
+	form.Add("crew_count", "4")
+
+	w := post(srv, "/edit", sessionID, form)
+	if !strings.Contains(w.Body.String(), "Crew count follows the roster") {
+		t.Error("Expected a crew count that disagrees with the roster to be rejected")
+	}
+	if edited, _ := srv.Board.Get(flight.ID); edited.CrewCount != 2 || edited.Type != "pickup" {
+		t.Errorf("Rejected edit should not change the flight, got %+v", edited)
+	}
+
+	form.Set("crew_count", "2")
+	post(srv, "/edit", sessionID, form)
+	if edited, _ := srv.Board.Get(flight.ID); edited.Type != "dropoff" {
+		t.Errorf("Expected an edit matching the roster to be saved, got type %q", edited.Type)
+	}
+}
@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
+	"shuttletracker/internal/web"
+)
+
+// TestMain keeps access logs from every request out of the test output
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// Passwords for the built-in accounts on every test server
+var passwords = map[string]string{
+	"valet": "valet-pass",
+	"desk":  "desk-pass",
+	"demo":  "demo123",
+}
+
+// harnessStart is a Monday morning in the hotel's default time zone
+var harnessStart = func() time.Time {
+	denver, _ := time.LoadLocation("America/Denver")
+	return time.Date(2025, 3, 3, 10, 0, 0, 0, denver)
+}()
+
+// fakeProvider answers lookups without calling an API. Flights arrive two
+// hours after the fake clock's time, or leave one hour after it when looked
+// up as departures, unless a canned answer was set, and numbers starting
+// with XX are never found. The canned answers are also the
+// airport's arrivals and departures.
+type fakeProvider struct {
+	clock *clock.Fake
+
+	mu      sync.Mutex
+	flights map[string]board.Flight
+	lookups int
+}
+
+func (p *fakeProvider) Lookup(flightNumber string) (board.Flight, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lookups++
+
+	if flight, ok := p.flights[flightNumber]; ok {
+		return flight, nil
+	}
+	if strings.HasPrefix(flightNumber, "XX") {
+		return board.Flight{}, fmt.Errorf("flight %s not found", flightNumber)
+	}
+	arrival := p.clock.Now().Add(2 * time.Hour)
+	return board.Flight{
+		FlightNumber:     flightNumber,
+		Airline:          "Test Air",
+		Status:           "Scheduled",
+		ScheduledArrival: arrival.Format("3:04 PM"),
+		ExpectedArrival:  arrival.Format("3:04 PM"),
+		SortTime:         arrival,
+	}, nil
+}
+
+func (p *fakeProvider) LookupDeparture(flightNumber, airport string, from, to time.Time) (board.Flight, error) {
+	p.mu.Lock()
+	_, canned := p.flights[flightNumber]
+	p.mu.Unlock()
+
+	flight, err := p.Lookup(flightNumber)
+	if err != nil || canned {
+		return flight, err
+	}
+	departure := p.clock.Now().Add(time.Hour)
+	flight.ScheduledArrival = departure.Format("3:04 PM")
+	flight.ExpectedArrival = departure.Format("3:04 PM")
+	flight.SortTime = departure
+	flight.Departing = true
+	return flight, nil
+}
+
+func (p *fakeProvider) Arrivals(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return p.listed(false), false, nil
+}
+
+func (p *fakeProvider) Departures(airport string, from, to time.Time) ([]board.Flight, bool, error) {
+	return p.listed(true), false, nil
+}
+
+// listed returns the canned arrivals or departures, soonest first
+func (p *fakeProvider) listed(departing bool) []board.Flight {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var flights []board.Flight
+	for _, flight := range p.flights {
+		if flight.Departing == departing {
+			flights = append(flights, flight)
+		}
+	}
+	sort.Slice(flights, func(i, j int) bool {
+		return flights[i].SortTime.Before(flights[j].SortTime)
+	})
+	return flights
+}
+
+// set gives a canned answer for a flight number
+func (p *fakeProvider) set(flight board.Flight) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flights[flight.FlightNumber] = flight
+}
+
+// harness boots the full handler stack with a fake provider and a fake
+// clock, so tests can drive the app through its forms the way a browser does
+type harness struct {
+	t        *testing.T
+	srv      *web.Server
+	handler  http.Handler
+	clock    *clock.Fake
+	provider *fakeProvider
+}
+
+// newHarness creates a server with the default config plus args, a
+// throwaway data directory and the fake clock stopped at harnessStart
+func newHarness(t *testing.T, args ...string) *harness {
+	t.Helper()
+	cfg, _, err := config.Load(append([]string{"-data-dir", t.TempDir()}, args...))
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	cfg.Auth.BcryptCost = bcrypt.MinCost
+	cfg.Auth.ValetPassword = passwords["valet"]
+	cfg.Auth.DeskPassword = passwords["desk"]
+	cfg.Auth.DemoPassword = passwords["demo"]
+
+	fake := clock.NewFake(harnessStart)
+	provider := &fakeProvider{clock: fake, flights: map[string]board.Flight{}}
+	srv, err := web.New(cfg, provider, fake)
+	if err != nil {
+		t.Fatalf("web.New failed: %v", err)
+	}
+
+	return &harness{t: t, srv: srv, handler: srv.Handler(), clock: fake, provider: provider}
+}
+
+// newTestServer returns a harness's server for tests that work with it directly
+func newTestServer(t *testing.T, args ...string) *web.Server {
+	t.Helper()
+	return newHarness(t, args...).srv
+}
+
+// flight finds a flight on the board by number
+func (h *harness) flight(flightNumber string) (board.Flight, bool) {
+	for _, flight := range h.srv.Board.Sorted() {
+		if flight.FlightNumber == flightNumber {
+			return flight, true
+		}
+	}
+	return board.Flight{}, false
+}
+
+// browser is one user's session against the harness
+type browser struct {
+	h       *harness
+	session *http.Cookie
+}
+
+// anonymous returns a browser that hasn't signed in
+func (h *harness) anonymous() *browser {
+	return &browser{h: h}
+}
+
+// login signs in through the login form and fails the test if no session
+// cookie comes back
+func (h *harness) login(username string) *browser {
+	h.t.Helper()
+	b := h.anonymous()
+	w := b.post("/login", url.Values{"username": {username}, "password": {passwords[username]}})
+	if w.Code != http.StatusSeeOther {
+		h.t.Fatalf("Login as %s: expected redirect (303), got %d", username, w.Code)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_id" && cookie.Value != "" {
+			b.session = cookie
+		}
+	}
+	if b.session == nil {
+		h.t.Fatalf("Login as %s set no session cookie", username)
+	}
+	return b
+}
+
+func (b *browser) do(req *http.Request) *httptest.ResponseRecorder {
+	if b.session != nil {
+		req.AddCookie(b.session)
+	}
+	w := httptest.NewRecorder()
+	b.h.handler.ServeHTTP(w, req)
+	return w
+}
+
+// get requests a page
+func (b *browser) get(path string) *httptest.ResponseRecorder {
+	return b.do(httptest.NewRequest("GET", path, nil))
+}
+
+// post submits a form
+func (b *browser) post(path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return b.do(req)
+}
+
+// get sends a GET through the full handler, signed in when sessionID is set
+func get(srv *web.Server, path, sessionID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	}
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
+
+// post sends a form POST through the full handler, signed in when sessionID is set
+func post(srv *web.Server, path, sessionID string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	}
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
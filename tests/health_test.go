@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"shuttletracker/internal/board"
+)
+
+func TestReadyzFailsWithoutStorage(t *testing.T) {
+	srv := newTestServer(t)
+
+	// A file where the data directory should be can't be written into
+	if err := os.RemoveAll(srv.Config.Server.DataDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(srv.Config.Server.DataDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := get(srv, "/readyz", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when storage is unreachable, got %d", w.Code)
+	}
+
+	// Liveness doesn't depend on storage
+	if w := get(srv, "/healthz", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected healthz to stay 200, got %d", w.Code)
+	}
+}
+
+func TestReadyzFailsWhileShuttingDown(t *testing.T) {
+	srv := newTestServer(t)
+
+	if w := get(srv, "/readyz", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 once ready, got %d: %s", w.Code, w.Body.String())
+	}
+
+	srv.StartShutdown()
+	if w := get(srv, "/readyz", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while shutting down, got %d", w.Code)
+	}
+}
+
+func TestBoardSurvivesRestart(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100", CrewCount: 3})
+	if err := srv.SaveBoard(); err != nil {
+		t.Fatalf("SaveBoard failed: %v", err)
+	}
+
+	restarted := newTestServer(t, "-data-dir", srv.Config.Server.DataDir)
+	if err := restarted.RestoreBoard(); err != nil {
+		t.Fatalf("RestoreBoard failed: %v", err)
+	}
+	if flights := restarted.Board.Sorted(); len(flights) != 1 || flights[0].FlightNumber != "AA100" {
+		t.Errorf("Board not restored: %+v", flights)
+	}
+}
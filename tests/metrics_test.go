@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shuttletracker/internal/board"
+)
+
+func TestMetricsHandlerReportsBoardAndToken(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{Status: "Scheduled", Stage: board.StagePending})
+	srv.Board.Add(board.Flight{Status: "Scheduled", Stage: board.StageDispatched})
+
+	w := get(srv, "/metrics", "")
+	if !strings.Contains(w.Body.String(), `shuttle_tracked_flights{status="scheduled"} 2`) {
+		t.Errorf("Expected tracked flights by status:\n%s", w.Body.String())
+	}
+
+	srv.Config.Server.MetricsToken = "s3cret"
+	w = get(srv, "/metrics", "")
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without the metrics token, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the metrics token, got %d", w.Code)
+	}
+}
@@ -1,13 +1,17 @@
-package main
+package tests
 
 import (
 	"strings"
 	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/auth"
 )
 
 func TestHashPassword(t *testing.T) {
 	password := "testpassword123"
-	hash := hashPassword(password)
+	hash := auth.HashPassword(password, bcrypt.DefaultCost)
 
 	if len(hash) != 60 {
 		t.Errorf("Expected hash length 60, got %d", len(hash))
@@ -20,8 +24,8 @@ func TestHashPassword(t *testing.T) {
 
 func TestHashPasswordUniqueness(t *testing.T) {
 	password := "samepassword"
-	hash1 := hashPassword(password)
-	hash2 := hashPassword(password)
+	hash1 := auth.HashPassword(password, bcrypt.DefaultCost)
+	hash2 := auth.HashPassword(password, bcrypt.DefaultCost)
 
 	// bcrypt should produce different hashes for the same password (due to salt)
 	if hash1 == hash2 {
@@ -31,41 +35,41 @@ func TestHashPasswordUniqueness(t *testing.T) {
 
 func TestCheckPasswordCorrect(t *testing.T) {
 	password := "correctpassword"
-	hash := hashPassword(password)
+	hash := auth.HashPassword(password, bcrypt.DefaultCost)
 
-	if !checkPassword(password, hash) {
+	if !auth.CheckPassword(password, hash) {
 		t.Error("Valid password failed to authenticate")
 	}
 }
 
 func TestCheckPasswordIncorrect(t *testing.T) {
 	password := "correctpassword"
-	hash := hashPassword(password)
+	hash := auth.HashPassword(password, bcrypt.DefaultCost)
 
-	if checkPassword("wrongpassword", hash) {
+	if auth.CheckPassword("wrongpassword", hash) {
 		t.Error("Invalid password incorrectly authenticated")
 	}
 }
 
 func TestCheckPasswordEmptyString(t *testing.T) {
 	password := "somepassword"
-	hash := hashPassword(password)
+	hash := auth.HashPassword(password, bcrypt.DefaultCost)
 
-	if checkPassword("", hash) {
+	if auth.CheckPassword("", hash) {
 		t.Error("Empty password should not authenticate")
 	}
 }
 
 func TestCheckPasswordCaseSensitive(t *testing.T) {
 	password := "Password123"
-	hash := hashPassword(password)
+	hash := auth.HashPassword(password, bcrypt.DefaultCost)
 
 	// Password should be case-sensitive
-	if checkPassword("password123", hash) {
+	if auth.CheckPassword("password123", hash) {
 		t.Error("Password check should be case-sensitive")
 	}
 
-	if checkPassword("PASSWORD123", hash) {
+	if auth.CheckPassword("PASSWORD123", hash) {
 		t.Error("Password check should be case-sensitive")
 	}
 }
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"shuttletracker/internal/board"
+	"shuttletracker/internal/web"
+)
+
+// allRoutes returns a path for every route registered by Server.Handler,
+// with a real file standing in for the static directory
+func allRoutes(srv *web.Server) []string {
+	var paths []string
+	for _, pattern := range srv.Routes() {
+		if pattern == "/static/" {
+			pattern = "/static/app.css"
+		}
+		paths = append(paths, pattern)
+	}
+	return paths
+}
+
+func TestSecurityHeadersOnEveryRoute(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100", Status: "Scheduled", Type: "pickup", CrewCount: 2, IsDelayed: true, Delay: 15})
+	handler := srv.Handler()
+	session := srv.Auth.CreateSession("desk")
+
+	routes := allRoutes(srv)
+	if len(routes) == 0 {
+		t.Fatal("Server lists no routes")
+	}
+	for _, route := range routes {
+		for _, signedIn := range []bool{false, true} {
+			req := httptest.NewRequest("GET", route, nil)
+			if signedIn {
+				req.AddCookie(&http.Cookie{Name: "session_id", Value: session})
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			header := w.Header()
+			csp := header.Get("Content-Security-Policy")
+			if !strings.Contains(csp, "script-src 'nonce-") || !strings.Contains(csp, "frame-ancestors 'none'") {
+				t.Errorf("%s (signed in %v): missing or weak CSP %q", route, signedIn, csp)
+			}
+			if header.Get("X-Content-Type-Options") != "nosniff" {
+				t.Errorf("%s (signed in %v): missing nosniff", route, signedIn)
+			}
+			if header.Get("X-Frame-Options") != "DENY" {
+				t.Errorf("%s (signed in %v): missing X-Frame-Options", route, signedIn)
+			}
+			if header.Get("Referrer-Policy") != "same-origin" {
+				t.Errorf("%s (signed in %v): missing Referrer-Policy", route, signedIn)
+			}
+		}
+	}
+}
+
+func TestPagesOnlyUseNoncedScriptsAndStyles(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Board.Add(board.Flight{FlightNumber: "AA100", Status: "Scheduled", Type: "pickup", CrewCount: 2, IsDelayed: true, Delay: 15})
+	handler := srv.Handler()
+	session := srv.Auth.CreateSession("desk")
+
+	inlineHandler := regexp.MustCompile(`\son[a-z]+="|\sstyle="`)
+	tag := regexp.MustCompile(`<(script|style)[^>]*>`)
+
+	for _, route := range []string{"/", "/login", "/print", "/calendar", "/history", "/reports", "/schedules", "/import", "/airport"} {
+		req := httptest.NewRequest("GET", route, nil)
+		if route != "/login" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: session})
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected the page to render, got %d", route, w.Code)
+		}
+		body := w.Body.String()
+
+		nonce := regexp.MustCompile(`'nonce-([^']+)'`).FindStringSubmatch(w.Header().Get("Content-Security-Policy"))
+		if nonce == nil {
+			t.Fatalf("%s: no nonce in CSP", route)
+		}
+		for _, found := range tag.FindAllString(body, -1) {
+			if !strings.Contains(found, `nonce="`+nonce[1]+`"`) {
+				t.Errorf("%s: %s doesn't carry the response nonce", route, found)
+			}
+		}
+		if match := inlineHandler.FindString(body); match != "" {
+			t.Errorf("%s: inline %q attribute would be blocked by the CSP", route, strings.TrimSpace(match))
+		}
+	}
+}
@@ -1,28 +1,36 @@
-package main
+package tests
 
 import (
 	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shuttletracker/internal/auth"
+	"shuttletracker/internal/clock"
+	"shuttletracker/internal/config"
 )
 
 func TestGenerateSessionID(t *testing.T) {
-	id1 := generateSessionID()
-	id2 := generateSessionID()
+	id1 := auth.NewToken()
+	id2 := auth.NewToken()
 
 	if len(id1) != 64 {
 		t.Errorf("Expected session ID length 64, got %d", len(id1))
 	}
 
 	if id1 == id2 {
-		t.Error("generateSessionID produced duplicate IDs")
+		t.Error("NewToken produced duplicate IDs")
 	}
 }
 
 func TestCreateSession(t *testing.T) {
+	store := newTestStore()
 	username := "testuser"
-	sessionID := createSession(username)
+	sessionID := store.CreateSession(username)
 
 	if sessionID == "" {
-		t.Fatal("createSession returned empty session ID")
+		t.Fatal("CreateSession returned empty session ID")
 	}
 
 	if len(sessionID) != 64 {
@@ -31,53 +39,56 @@ func TestCreateSession(t *testing.T) {
 }
 
 func TestGetSession(t *testing.T) {
+	store := newTestStore()
 	username := "testuser"
-	sessionID := createSession(username)
+	sessionID := store.CreateSession(username)
 
-	retrievedUsername := getSession(sessionID)
+	retrievedUsername := store.Session(sessionID)
 	if retrievedUsername != username {
 		t.Errorf("Expected username %s, got %s", username, retrievedUsername)
 	}
 
 	// Test invalid session
-	invalidUsername := getSession("invalidsessionid")
+	invalidUsername := store.Session("invalidsessionid")
 	if invalidUsername != "" {
-		t.Error("getSession should return empty string for invalid session")
+		t.Error("Session should return empty string for invalid session")
 	}
 }
 
 func TestDeleteSession(t *testing.T) {
+	store := newTestStore()
 	username := "testuser"
-	sessionID := createSession(username)
+	sessionID := store.CreateSession(username)
 
 	// Verify session exists
-	if getSession(sessionID) != username {
+	if store.Session(sessionID) != username {
 		t.Fatal("Session was not created properly")
 	}
 
 	// Delete session
-	deleteSession(sessionID)
+	store.DeleteSession(sessionID)
 
 	// Verify session is gone
-	retrievedUsername := getSession(sessionID)
+	retrievedUsername := store.Session(sessionID)
 	if retrievedUsername != "" {
 		t.Error("Session was not properly deleted")
 	}
 }
 
 func TestSessionConcurrency(t *testing.T) {
+	store := newTestStore()
 	// Test that concurrent session operations don't cause race conditions
 	done := make(chan bool)
 
 	for i := 0; i < 10; i++ {
 		go func(id int) {
 			username := "user" + string(rune(id))
-			sessionID := createSession(username)
-			retrieved := getSession(sessionID)
+			sessionID := store.CreateSession(username)
+			retrieved := store.Session(sessionID)
 			if retrieved != username {
 				t.Errorf("Concurrent session test failed for %s", username)
 			}
-			deleteSession(sessionID)
+			store.DeleteSession(sessionID)
 			done <- true
 		}(i)
 	}
@@ -86,3 +97,22 @@ func TestSessionConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+func TestSessionExpires(t *testing.T) {
+	fake := clock.NewFake(harnessStart)
+	store := auth.NewStore(config.AuthConfig{BcryptCost: bcrypt.MinCost}, fake)
+	sessionID := store.CreateSession("valet")
+
+	fake.Advance(auth.SessionTTL - time.Minute)
+	if store.Session(sessionID) != "valet" {
+		t.Error("Session should still be valid just before it expires")
+	}
+
+	fake.Advance(time.Minute)
+	if store.Session(sessionID) != "" {
+		t.Error("Session should expire after SessionTTL")
+	}
+	if store.ActiveSessions() != 0 {
+		t.Errorf("Expected no active sessions, got %d", store.ActiveSessions())
+	}
+}
@@ -0,0 +1,18 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSessionCookieSecureWithTLS(t *testing.T) {
+	srv := newTestServer(t, "-tls-self-signed")
+
+	form := url.Values{"username": {"demo"}, "password": {"demo123"}}
+	w := post(srv, "/login", "", form)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 || !cookies[0].Secure {
+		t.Errorf("Expected a Secure session cookie when HTTPS is on, got %+v", cookies)
+	}
+}